@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+// phoneTables are the tables holding customer phones with the key column of their rows.
+// Each service has its own database, so only the tables of the given database are touched.
+var phoneTables = map[string]string{
+	"orders":            "id",        // order-service
+	"promo_code_usages": "id",        // order-service
+	"phone_flags":       "phone_key", // order-service
+	"users":             "id",        // user-service
+	"reviews":           "id",        // web-service
+}
+
+// Backfills phones typed before they were normalized, rewriting them to E.164.
+// Phones that can't be parsed are reported and left as they are. A normalized phone
+// normalizes to itself and each table is rewritten in one transaction, so the backfill
+// is idempotent and safe to run again, e.g. after a failed run.
+//
+//	go run ./cmd/normalize-phones -dry-run
+//
+// The order, user and web production images ship it as normalize-phones, inside a
+// service container the database is taken from its DB_* variables (make phones-normalize).
+func main() {
+	_ = godotenv.Load(".env")
+	_ = godotenv.Load("../.env")
+	_ = godotenv.Load("../../.env")
+
+	dsn := flag.String("dsn", databaseURL(), "Postgres connection URL of the service database")
+	dryRun := flag.Bool("dry-run", false, "Only report the changes")
+	flag.Parse()
+
+	if *dsn == "" {
+		log.Fatal("-dsn or DB_HOST and DB_NAME are required")
+	}
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if err = db.PingContext(ctx); err != nil {
+		log.Fatalf("Error pinging database: %v", err)
+	}
+
+	for table, key := range phoneTables {
+		var exists bool
+		if err := db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", "public."+table).Scan(&exists); err != nil {
+			log.Fatalf("Error checking table %s: %v", table, err)
+		}
+		if !exists {
+			continue
+		}
+
+		updated, invalid, err := normalizeTable(ctx, db, table, key, *dryRun)
+		if err != nil {
+			log.Fatalf("Error normalizing %s: %v", table, err)
+		}
+		fmt.Printf("%s: %d normalized, %d invalid\n", table, updated, invalid)
+	}
+}
+
+// databaseURL builds the connection URL from the DB_* variables the services use
+func databaseURL() string {
+	host, name := os.Getenv("DB_HOST"), os.Getenv("DB_NAME")
+	if host == "" || name == "" {
+		return ""
+	}
+
+	port := os.Getenv("DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	sslMode := os.Getenv("DB_SSLMODE")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), host, port, name, sslMode)
+}
+
+func normalizeTable(ctx context.Context, db *sql.DB, table, key string, dryRun bool) (int, int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT %s::text, phone FROM %s WHERE phone IS NOT NULL AND phone <> ''`, key, table))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	changes := make(map[string]string)
+	invalid := 0
+	for rows.Next() {
+		var id, phone string
+		if err := rows.Scan(&id, &phone); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+
+		normalized, err := validator.NormalizePhone(phone)
+		if err != nil {
+			invalid++
+			log.Printf("%s %s: can't normalize %q", table, id, phone)
+			continue
+		}
+		if normalized != phone {
+			changes[id] = normalized
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	if dryRun {
+		return len(changes), invalid, nil
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET phone = $1 WHERE %s = $2`, table, key)
+	for id, phone := range changes {
+		if _, err := tx.ExecContext(ctx, query, phone, id); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return len(changes), invalid, tx.Commit()
+}
@@ -59,6 +59,12 @@ func NewServer(cfg ServerConfig) *Server {
 	s.app.Use(recover.New())
 	s.app.Use(logger.New())
 	s.app.Use(requestid.New())
+	s.app.Use(func(c fiber.Ctx) error {
+		// Identity headers are only set by the JWT middleware, never trusted from clients
+		c.Request().Header.Del("X-User-ID")
+		c.Request().Header.Del("X-User-Email")
+		return c.Next()
+	})
 	s.app.Use(func(c fiber.Ctx) error {
 		// Debug logging for CORS
 		origin := c.Get("Origin")
@@ -70,7 +76,7 @@ func NewServer(cfg ServerConfig) *Server {
 	s.app.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3005", "http://localhost:4000", "http://127.0.0.1:3000", "http://127.0.0.1:3005", "http://127.0.0.1:4000"},
 		AllowCredentials: true,
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Idempotency-Key"},
 		AllowMethods:     []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH", "OPTIONS"},
 	}))
 	s.app.Use(etag.New())
@@ -155,6 +161,9 @@ func (s *Server) SetupRoutes() {
 	jwtMiddleware := middleware.NewJWTMiddleware(middleware.JWTConfig{
 		Secret: s.jwtSecret,
 	})
+	optionalJWTMiddleware := middleware.NewOptionalJWTMiddleware(middleware.JWTConfig{
+		Secret: s.jwtSecret,
+	})
 
 	// Telegram webhook
 	telegramGroup := s.app.Group("webhooks/telegram")
@@ -188,14 +197,46 @@ func (s *Server) SetupRoutes() {
 	reviewsGroup.Use(jwtMiddleware)
 	reviewsGroup.Delete("/:id", s.ProxyToWebService, middleware.AdminOnly)
 
-	// Orders (public POST, admin GET/PUT/DELETE)
+	// Orders (public POST, linked to the customer when signed in; admin GET/PUT/DELETE)
 	ordersGroup := s.app.Group("/orders")
-	ordersGroup.Post("/", s.ProxyToOrderService)
+	ordersGroup.Post("/", s.ProxyToOrderService, optionalJWTMiddleware)
+	ordersGroup.Post("/quote", s.ProxyToOrderService)
+	ordersGroup.Get("/slots", s.ProxyToOrderService)
+	ordersGroup.Get("/track/:token", s.ProxyToOrderService)
 	ordersGroup.Use(jwtMiddleware)
 	ordersGroup.Get("/", s.ProxyToOrderService, middleware.AdminOnly)
+	ordersGroup.Get("/export", s.ProxyToOrderService, middleware.AdminOnly)
 	ordersGroup.Get("/:id", s.ProxyToOrderService, middleware.AdminOnly)
 	ordersGroup.Put("/:id", s.ProxyToOrderService, middleware.AdminOnly)
 	ordersGroup.Delete("/:id", s.ProxyToOrderService, middleware.AdminOnly)
+	ordersGroup.Patch("/:id/status", s.ProxyToOrderService, middleware.AdminOnly)
+	ordersGroup.Get("/:id/status-history", s.ProxyToOrderService, middleware.AdminOnly)
+	ordersGroup.Post("/:id/release", s.ProxyToOrderService, middleware.AdminOnly)
+	ordersGroup.Post("/:id/cancel", s.ProxyToOrderService, middleware.AdminOnly)
+
+	// Promo codes (admin)
+	promoGroup := s.app.Group("/promo-codes")
+	promoGroup.Use(jwtMiddleware)
+	promoGroup.Get("/", s.ProxyToOrderService, middleware.AdminOnly)
+	promoGroup.Get("/:id", s.ProxyToOrderService, middleware.AdminOnly)
+	promoGroup.Post("/", s.ProxyToOrderService, middleware.AdminOnly)
+	promoGroup.Put("/:id", s.ProxyToOrderService, middleware.AdminOnly)
+	promoGroup.Delete("/:id", s.ProxyToOrderService, middleware.AdminOnly)
+
+	// Phone risk flags (admin)
+	riskGroup := s.app.Group("/risk/phones")
+	riskGroup.Use(jwtMiddleware)
+	riskGroup.Get("/", s.ProxyToOrderService, middleware.AdminOnly)
+	riskGroup.Get("/:phone", s.ProxyToOrderService, middleware.AdminOnly)
+	riskGroup.Put("/:phone", s.ProxyToOrderService, middleware.AdminOnly)
+	riskGroup.Delete("/:phone", s.ProxyToOrderService, middleware.AdminOnly)
+
+	// Analytics (admin)
+	analyticsGroup := s.app.Group("/analytics")
+	analyticsGroup.Use(jwtMiddleware)
+	analyticsGroup.Get("/sales", s.ProxyToOrderService, middleware.AdminOnly)
+	analyticsGroup.Get("/top-products", s.ProxyToOrderService, middleware.AdminOnly)
+	analyticsGroup.Get("/top-variations", s.ProxyToOrderService, middleware.AdminOnly)
 
 	// Payment Service
 	paymentGroup := s.app.Group("/payment-service")
@@ -216,6 +257,9 @@ func (s *Server) SetupRoutes() {
 	userGroup := s.app.Group("/user")
 	userGroup.Use(jwtMiddleware)
 	userGroup.Get("/me", s.ProxyToUserService)
+	userGroup.Get("/me/loyalty", s.ProxyToUserService)
+	userGroup.Get("/orders", s.ProxyToOrderService)
+	userGroup.Get("/orders/:id", s.ProxyToOrderService)
 	userGroup.Get("/:id", s.ProxyToUserService, middleware.AdminOnly)
 
 	// Products
@@ -15,33 +15,64 @@ type JWTConfig struct {
 
 func NewJWTMiddleware(cfg JWTConfig) fiber.Handler {
 	return fiber.Handler(func(c fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
-			return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
-		}
-
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenStr == authHeader {
+		claims, ok := parseClaims(c, cfg.Secret)
+		if !ok {
 			return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
 		}
 
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return cfg.Secret, nil
-		})
+		setIdentity(c, claims)
 
-		if err != nil || !token.Valid {
-			return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
-		}
+		return c.Next()
+	})
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+// NewOptionalJWTMiddleware identifies the caller when a valid token is sent and lets
+// anonymous requests through, a missing or invalid token is not an error
+func NewOptionalJWTMiddleware(cfg JWTConfig) fiber.Handler {
+	return fiber.Handler(func(c fiber.Ctx) error {
+		if claims, ok := parseClaims(c, cfg.Secret); ok {
+			setIdentity(c, claims)
 		}
 
-		c.Locals("user_claims", claims)
 		return c.Next()
 	})
 }
+
+// parseClaims validates the bearer token of the request and returns its claims
+func parseClaims(c fiber.Ctx, secret []byte) (jwt.MapClaims, bool) {
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		return nil, false
+	}
+
+	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenStr == authHeader {
+		return nil, false
+	}
+
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return secret, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	return claims, ok
+}
+
+func setIdentity(c fiber.Ctx, claims jwt.MapClaims) {
+	c.Locals("user_claims", claims)
+
+	// Forward the caller identity to downstream services
+	if userID, ok := claims["user_id"].(string); ok {
+		c.Request().Header.Set("X-User-ID", userID)
+	}
+	if email, ok := claims["email"].(string); ok {
+		c.Request().Header.Set("X-User-Email", email)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +10,9 @@ import (
 	"github.com/tonysanin/brobar/pkg/helpers"
 )
 
+// CourierDispatched is the courier status order-service shows once the taxi is called
+const CourierDispatched = "dispatched"
+
 type OrderDTO struct {
 	ID            string `json:"id"`
 	Name          string `json:"name"`
@@ -57,3 +61,24 @@ func (c *OrderClient) GetOrder(id string) (*OrderDTO, error) {
 
 	return &result.Data, nil
 }
+
+// SetCourierStatus reports the delivery state to order-service for the customer tracking page
+func (c *OrderClient) SetCourierStatus(id, status string) error {
+	body, err := json.Marshal(map[string]string{"status": status})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/orders/%s/courier", c.baseURL, id)
+	resp, err := c.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to set courier status: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -256,6 +256,10 @@ func (c *Consumer) handleTaxiConfirm(body []byte) error {
 	
 	log.Printf("Ontaxi order created response: %s", resp)
 
+	if err := c.orderClient.SetCourierStatus(req.OrderID, service.CourierDispatched); err != nil {
+		log.Printf("Failed to report courier status for order %s: %v", req.OrderID, err)
+	}
+
 	evt := TaxiOrdered{
 		ChatID:  req.ChatID,
 		OrderID: req.OrderID,
@@ -44,6 +44,7 @@ func main() {
 	// Initialize clients
 	productClient := clients.NewProductClient()
 	webClient := clients.NewWebClient()
+	userClient := clients.NewUserClient()
 	paymentClient := payment.NewClient(cfg.PaymentServiceURL)
 
 	// Initialize Message Broker
@@ -53,10 +54,26 @@ func main() {
 	// Initialize repositories
 	orderRepository := repositories.NewOrderRepository(db)
 	orderItemsRepository := repositories.NewOrderItemRepository(db)
+	promoRepository := repositories.NewPromoRepository(db)
+	phoneFlagRepository := repositories.NewPhoneFlagRepository(db)
+	statusHistoryRepository := repositories.NewStatusHistoryRepository(db)
+	idempotencyRepository := repositories.NewIdempotencyRepository(db)
+	analyticsRepository := repositories.NewAnalyticsRepository(db)
+	outboxRepository := repositories.NewOutboxRepository(db)
 
 	// Initialize services
 	validationService := services.NewValidationService(productClient, webClient)
-	orderService := services.NewOrderService(orderRepository, orderItemsRepository, productClient, paymentClient, validationService, producer, cfg.AppTimezone)
+	promoService := services.NewPromoService(promoRepository)
+	riskService := services.NewRiskService(phoneFlagRepository, orderRepository)
+	if cfg.QuoteSecret == "" {
+		log.Fatalf("ORDER_QUOTE_SECRET must be set")
+	}
+	quoteSigner := services.NewQuoteSigner(cfg.QuoteSecret, cfg.QuoteTTL)
+	orderService := services.NewOrderService(db, orderRepository, orderItemsRepository, statusHistoryRepository, productClient, paymentClient, userClient, validationService, promoService, riskService, quoteSigner, outboxRepository, cfg.AppTimezone, cfg.PaymentTTL, cfg.PrepLeadTimes)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepository, orderService, cfg.IdempotencyTTL)
+	analyticsService := services.NewAnalyticsService(analyticsRepository, orderService.Location())
+	outboxRelay := services.NewOutboxRelay(outboxRepository, producer)
+	outboxRelay.Handle(services.OutboxLoyalty, orderService.DeliverLoyaltyEvent)
 
 	// Initialize Consumer
 	paymentConsumer, err := consumer.NewPaymentConsumer(cfg.RabbitMQURL, orderService)
@@ -69,7 +86,19 @@ func main() {
 	}
 	defer paymentConsumer.Stop()
 
-	server := api.NewServer(orderService)
+	// Cancel online orders whose invoice was never paid
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	defer stopExpiry()
+	go orderService.RunPaymentExpiry(expiryCtx, time.Minute)
+	go idempotencyService.RunCleanup(expiryCtx, time.Hour)
+
+	// Send held pre-orders to the kitchen when their prep time comes
+	go orderService.RunScheduledRelease(expiryCtx, time.Minute)
+
+	// Publish order events written to the outbox
+	go outboxRelay.Run(expiryCtx, time.Second)
+
+	server := api.NewServer(orderService, promoService, riskService, idempotencyService, analyticsService)
 
 	log.Printf("Starting order service on :%s", cfg.Port)
 	if err := server.Listen(":" + cfg.Port); err != nil {
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/services"
+	"github.com/tonysanin/brobar/pkg/response"
+)
+
+type AnalyticsHandler struct {
+	service      *services.AnalyticsService
+	orderService *services.OrderService
+}
+
+func NewAnalyticsHandler(service *services.AnalyticsService, orderService *services.OrderService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, orderService: orderService}
+}
+
+// GetSales returns revenue, order count and average check per bucket of group_by
+func (h *AnalyticsHandler) GetSales(c fiber.Ctx) error {
+	grouping := models.SalesGrouping(c.Query("group_by", string(models.SalesByDay)))
+	switch grouping {
+	case models.SalesByDay, models.SalesByHour, models.SalesByHourOfDay,
+		models.SalesByDeliveryType, models.SalesByZone, models.SalesByPaymentMethod:
+	default:
+		return response.BadRequest(c, errors.New("group_by must be one of day, hour, hour_of_day, delivery_type, zone, payment_method"))
+	}
+
+	filter, err := parseOrderFilter(c, h.orderService.Location())
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	rows, err := h.service.GetSales(c.Context(), filter, grouping)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, rows)
+}
+
+func (h *AnalyticsHandler) GetTopProducts(c fiber.Ctx) error {
+	filter, sort, limit, err := parseTopQuery(c, h.orderService)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	rows, err := h.service.GetTopProducts(c.Context(), filter, sort, limit)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, rows)
+}
+
+func (h *AnalyticsHandler) GetTopVariations(c fiber.Ctx) error {
+	filter, sort, limit, err := parseTopQuery(c, h.orderService)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	rows, err := h.service.GetTopVariations(c.Context(), filter, sort, limit)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, rows)
+}
+
+// parseTopQuery reads the filters, sort (quantity or revenue) and limit of a top list
+func parseTopQuery(c fiber.Ctx, orderService *services.OrderService) (models.OrderFilter, models.TopSort, int, error) {
+	sort := models.TopSort(c.Query("sort", string(models.TopByQuantity)))
+	if sort != models.TopByQuantity && sort != models.TopByRevenue {
+		return models.OrderFilter{}, "", 0, errors.New("sort must be quantity or revenue")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filter, err := parseOrderFilter(c, orderService.Location())
+	if err != nil {
+		return models.OrderFilter{}, "", 0, err
+	}
+
+	return filter, sort, limit, nil
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"strconv"
 	"time"
 
@@ -9,17 +13,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/tonysanin/brobar/order-service/internal/api/requests"
 	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/export"
 	"github.com/tonysanin/brobar/order-service/internal/models"
 	"github.com/tonysanin/brobar/order-service/internal/services"
 	"github.com/tonysanin/brobar/pkg/response"
 )
 
+// maxIdempotencyKeyLength matches the idempotency_keys.key column
+const maxIdempotencyKeyLength = 255
+
 type OrderHandler struct {
-	service *services.OrderService
+	service            *services.OrderService
+	idempotencyService *services.IdempotencyService
 }
 
-func NewOrderHandler(service *services.OrderService) *OrderHandler {
-	return &OrderHandler{service: service}
+func NewOrderHandler(service *services.OrderService, idempotencyService *services.IdempotencyService) *OrderHandler {
+	return &OrderHandler{service: service, idempotencyService: idempotencyService}
 }
 
 func (h *OrderHandler) GetOrder(c fiber.Ctx) error {
@@ -68,9 +77,14 @@ func (h *OrderHandler) GetOrders(c fiber.Ctx) error {
 		orderDir = "desc"
 	}
 
+	filter, err := parseOrderFilter(c, h.service.Location())
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
 	offset := (page - 1) * limit
 
-	orders, totalCount, err := h.service.GetOrdersWithPagination(c.Context(), limit, offset, orderBy, orderDir)
+	orders, totalCount, err := h.service.GetOrdersWithPagination(c.Context(), filter, limit, offset, orderBy, orderDir)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err)
 	}
@@ -89,6 +103,53 @@ func (h *OrderHandler) GetOrders(c fiber.Ctx) error {
 	return response.Success(c, resp)
 }
 
+// ExportOrders streams the filtered orders with their items as CSV or XLSX
+func (h *OrderHandler) ExportOrders(c fiber.Ctx) error {
+	format := export.Format(c.Query("format", string(export.FormatCSV)))
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		return response.BadRequest(c, errors.New("format must be csv or xlsx"))
+	}
+
+	filter, err := parseOrderFilter(c, h.service.Location())
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Attachment(fmt.Sprintf("orders-%s.%s", time.Now().In(h.service.Location()).Format("2006-01-02"), format))
+
+	// The body is written after the handler returns, so the request context can't be used
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := h.service.ExportOrders(context.Background(), filter, format, w); err != nil {
+			log.Printf("order export failed: %v", err)
+		}
+	})
+}
+
+// parseOrderFilter reads the order list filters from the query string,
+// date-only values are taken in the given location
+func parseOrderFilter(c fiber.Ctx, loc *time.Location) (models.OrderFilter, error) {
+	req := requests.OrderFilterRequest{
+		Status:        c.Query("status"),
+		DeliveryType:  c.Query("delivery_type"),
+		PaymentMethod: c.Query("payment_method"),
+		Zone:          c.Query("zone"),
+		CreatedFrom:   c.Query("created_from"),
+		CreatedTo:     c.Query("created_to"),
+		TimeFrom:      c.Query("time_from"),
+		TimeTo:        c.Query("time_to"),
+		Phone:         c.Query("phone"),
+		Name:          c.Query("name"),
+		TotalMin:      c.Query("total_min"),
+		TotalMax:      c.Query("total_max"),
+		Scheduled:     c.Query("scheduled"),
+	}
+	if err := req.Validate(); err != nil {
+		return models.OrderFilter{}, err
+	}
+	return req.ToModel(loc)
+}
+
 // CreateOrder handles public order creation from frontend
 func (h *OrderHandler) CreateOrder(c fiber.Ctx) error {
 	var req requests.CreateOrderRequest
@@ -101,50 +162,165 @@ func (h *OrderHandler) CreateOrder(c fiber.Ctx) error {
 		return response.BadRequest(c, err)
 	}
 
+	items, err := mapOrderItems(req.Items)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
 	// Map to input for service
 	input := &services.CreateOrderInput{
-		Name:           req.Name,
+		PricingInput: services.PricingInput{
+			Phone:          req.Phone,
+			DeliveryTypeID: req.DeliveryTypeID,
+			DeliveryDoor:   req.DeliveryDoor,
+			Coords:         req.Coords,
+			PromoCode:      req.PromoCode,
+			LoyaltyPoints:  req.LoyaltyPoints,
+			Items:          items,
+		},
+		Name:          req.Name,
+		Email:         req.Email,
+		Address:       req.Address,
+		Zone:          req.Zone,
+		Entrance:      req.Entrance,
+		Time:          req.Time,
+		PaymentMethod: req.PaymentMethod,
+		Cutlery:       req.Cutlery,
+		Wishes:        req.Wishes,
+		ClientTotal:   req.ClientTotal,
+		QuoteToken:    req.QuoteToken,
+		UserID:        requestUserID(c),
+	}
+
+	var order *models.Order
+
+	// Retries with the same Idempotency-Key get the original order instead of a duplicate
+	if key := c.Get("Idempotency-Key"); key != "" {
+		if len(key) > maxIdempotencyKeyLength {
+			return response.BadRequest(c, errors.New("idempotency key is too long"))
+		}
+
+		var replayed bool
+		order, replayed, err = h.idempotencyService.CreateOrder(c.Context(), key, input)
+		if replayed {
+			c.Set("Idempotent-Replayed", "true")
+		}
+	} else {
+		order, err = h.service.CreateOrderFromInput(c.Context(), input)
+	}
+
+	if err != nil {
+		if errors.Is(err, services.ErrIdempotencyKeyInFlight) {
+			return response.ErrorWithCode(c, fiber.StatusConflict, "idempotency_in_progress", err)
+		}
+		if errors.Is(err, services.ErrIdempotencyKeyReused) {
+			return response.ErrorWithCode(c, fiber.StatusUnprocessableEntity, "idempotency_key_reused", err)
+		}
+		return orderInputError(c, err)
+	}
+
+	return response.Success(c, order)
+}
+
+// QuoteOrder prices the cart the same way order creation does
+func (h *OrderHandler) QuoteOrder(c fiber.Ctx) error {
+	var req requests.QuoteOrderRequest
+
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	items, err := mapOrderItems(req.Items)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	quote, err := h.service.QuoteOrder(c.Context(), &services.PricingInput{
 		Phone:          req.Phone,
-		Email:          req.Email,
 		DeliveryTypeID: req.DeliveryTypeID,
-		Address:        req.Address,
-		Zone:           req.Zone,
-		Entrance:       req.Entrance,
 		DeliveryDoor:   req.DeliveryDoor,
 		Coords:         req.Coords,
-		Time:           req.Time,
-		PaymentMethod:  req.PaymentMethod,
-		Cutlery:        req.Cutlery,
 		PromoCode:      req.PromoCode,
-		Wishes:         req.Wishes,
-		ClientTotal:    req.ClientTotal,
-		Items:          make([]services.OrderItemInput, len(req.Items)),
+		LoyaltyPoints:  req.LoyaltyPoints,
+		Items:          items,
+	})
+	if err != nil {
+		return orderInputError(c, err)
 	}
 
-	for i, itemReq := range req.Items {
+	return response.Success(c, quote)
+}
+
+func mapOrderItems(reqs []requests.OrderItemRequest) ([]services.OrderItemInput, error) {
+	items := make([]services.OrderItemInput, len(reqs))
+	for i, itemReq := range reqs {
 		if err := itemReq.Validate(); err != nil {
-			return response.BadRequest(c, err)
+			return nil, err
 		}
 
-		input.Items[i] = services.OrderItemInput{
+		items[i] = services.OrderItemInput{
 			ProductID:          itemReq.ProductID,
 			ProductVariationID: itemReq.ProductVariationID,
+			Variations:         make([]services.VariationSelection, len(itemReq.Variations)),
 			Quantity:           itemReq.Quantity,
 		}
-	}
-
-	order, err := h.service.CreateOrderFromInput(c.Context(), input)
-	if err != nil {
-		// Return validation errors as bad request
-		if errors.Is(err, services.ErrTimeNotAvailable) ||
-			errors.Is(err, services.ErrPriceMismatch) ||
-			errors.Is(err, services.ErrProductNotFound) {
-			return response.BadRequest(c, err)
+		for j, variationReq := range itemReq.Variations {
+			if err := variationReq.Validate(); err != nil {
+				return nil, err
+			}
+			items[i].Variations[j] = services.VariationSelection{
+				VariationID: variationReq.VariationID,
+				Quantity:    variationReq.Quantity,
+			}
 		}
-		return response.Error(c, fiber.StatusInternalServerError, err)
 	}
+	return items, nil
+}
 
-	return response.Success(c, order)
+// orderInputError maps errors caused by the customer's cart or checkout data
+func orderInputError(c fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrSalesPaused) {
+		return response.ErrorWithCode(c, fiber.StatusServiceUnavailable, "sales_paused", err)
+	}
+	if errors.Is(err, services.ErrSlotFull) {
+		return response.ErrorWithCode(c, fiber.StatusConflict, "slot_full", err)
+	}
+	if errors.Is(err, services.ErrPhoneBlocked) {
+		return response.ErrorWithCode(c, fiber.StatusForbidden, "phone_blocked", err)
+	}
+	if errors.Is(err, services.ErrPhoneOnlineOnly) {
+		return response.ErrorWithCode(c, fiber.StatusUnprocessableEntity, "online_payment_required", err)
+	}
+	if errors.Is(err, services.ErrQuoteExpired) {
+		return response.ErrorWithCode(c, fiber.StatusBadRequest, "quote_expired", err)
+	}
+	// Return validation errors as bad request
+	if errors.Is(err, services.ErrTimeNotAvailable) ||
+		errors.Is(err, services.ErrPriceMismatch) ||
+		errors.Is(err, services.ErrQuoteInvalid) ||
+		errors.Is(err, services.ErrProductNotFound) ||
+		errors.Is(err, services.ErrOutOfStock) ||
+		errors.Is(err, services.ErrBelowZoneMinimum) ||
+		errors.Is(err, services.ErrPaymentMethodNotInZone) ||
+		errors.Is(err, services.ErrVariationNotFound) ||
+		errors.Is(err, services.ErrVariationRequired) ||
+		errors.Is(err, services.ErrPromoNotFound) ||
+		errors.Is(err, services.ErrPromoInactive) ||
+		errors.Is(err, services.ErrPromoMinCart) ||
+		errors.Is(err, services.ErrPromoUsageLimit) ||
+		errors.Is(err, services.ErrPromoNotApplicable) ||
+		errors.Is(err, services.ErrLoyaltyDisabled) ||
+		errors.Is(err, services.ErrLoyaltySignInRequired) ||
+		errors.Is(err, services.ErrLoyaltyCapExceeded) ||
+		errors.Is(err, services.ErrLoyaltyInsufficient) ||
+		errors.Is(err, services.ErrInvalidPhone) {
+		return response.BadRequest(c, err)
+	}
+	return response.Error(c, fiber.StatusInternalServerError, err)
 }
 
 func (h *OrderHandler) UpdateOrder(c fiber.Ctx) error {
@@ -207,11 +383,17 @@ func (h *OrderHandler) UpdateOrder(c fiber.Ctx) error {
 		order.UpdatedAt = time.Now()
 	}
 
-	err = h.service.UpdateOrder(c.Context(), &order)
+	err = h.service.UpdateOrder(c.Context(), &order, changedBy(c))
 	if err != nil {
 		if errors.Is(err, customerrors.OrderNotFound) {
 			return response.NotFound(c)
 		}
+		if errors.Is(err, services.ErrInvalidStatusTransition) ||
+			errors.Is(err, services.ErrStatusPaidViaPayment) ||
+			errors.Is(err, services.ErrStatusCancelledViaCancel) ||
+			errors.Is(err, services.ErrInvalidPhone) {
+			return response.BadRequest(c, err)
+		}
 		return response.Error(c, fiber.StatusInternalServerError, err)
 	}
 
@@ -255,3 +437,202 @@ func (h *OrderHandler) MarkSyrveNotified(c fiber.Ctx) error {
 		"notified": notified,
 	})
 }
+
+// SetCourierStatus records the taxi state of a delivery
+func (h *OrderHandler) SetCourierStatus(c fiber.Ctx) error {
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	var req requests.CourierStatusRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := h.service.SetCourierStatus(c.Context(), id, req.Status); err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, nil)
+}
+
+// TrackOrder is the public order status page, authorized by the order's tracking token
+func (h *OrderHandler) TrackOrder(c fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return response.NotFound(c)
+	}
+
+	tracking, err := h.service.GetOrderTracking(c.Context(), token)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, tracking)
+}
+
+// GetAvailableSlots lists the order times of a date for the checkout
+func (h *OrderHandler) GetAvailableSlots(c fiber.Ctx) error {
+	req := requests.AvailableSlotsRequest{
+		Date:         c.Query("date"),
+		DeliveryType: c.Query("delivery_type", string(models.DeliveryTypeDelivery)),
+	}
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	slots, err := h.service.GetAvailableSlots(c.Context(), req.Date, models.DeliveryType(req.DeliveryType))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, slots)
+}
+
+// ReleaseScheduledOrder sends a held pre-order to the kitchen before its release time
+func (h *OrderHandler) ReleaseScheduledOrder(c fiber.Ctx) error {
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	order, err := h.service.ReleaseScheduledOrder(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		if errors.Is(err, services.ErrOrderNotScheduled) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, order)
+}
+
+func (h *OrderHandler) UpdateOrderStatus(c fiber.Ctx) error {
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	var req requests.UpdateOrderStatusRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	order, err := h.service.ChangeOrderStatus(c.Context(), id, models.Status(req.StatusID), changedBy(c), req.Reason)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		if errors.Is(err, services.ErrInvalidStatusTransition) ||
+			errors.Is(err, services.ErrStatusPaidViaPayment) {
+			return response.BadRequest(c, err)
+		}
+		if errors.Is(err, services.ErrStatusConflict) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, services.ErrRefundFailed) ||
+			errors.Is(err, services.ErrInvoiceCancelFailed) {
+			return response.Error(c, fiber.StatusBadGateway, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, order)
+}
+
+// CancelOrder cancels the order, refunding a paid one and cancelling it in Syrve
+func (h *OrderHandler) CancelOrder(c fiber.Ctx) error {
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	var req requests.CancelOrderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	order, err := h.service.CancelOrder(c.Context(), id, changedBy(c), req.Reason, req.NoShow)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		if errors.Is(err, services.ErrInvalidStatusTransition) {
+			return response.BadRequest(c, err)
+		}
+		if errors.Is(err, services.ErrStatusConflict) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, services.ErrRefundFailed) ||
+			errors.Is(err, services.ErrInvoiceCancelFailed) {
+			return response.Error(c, fiber.StatusBadGateway, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, order)
+}
+
+func (h *OrderHandler) GetOrderStatusHistory(c fiber.Ctx) error {
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	history, err := h.service.GetOrderStatusHistory(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, history)
+}
+
+// requestUserID is the signed-in customer as forwarded by the gateway, nil for guests
+func requestUserID(c fiber.Ctx) *uuid.UUID {
+	userID, err := uuid.Parse(c.Get("X-User-ID"))
+	if err != nil {
+		return nil
+	}
+	return &userID
+}
+
+// changedBy identifies the admin making a change, as forwarded by the gateway
+func changedBy(c fiber.Ctx) string {
+	if email := c.Get("X-User-Email"); email != "" {
+		return email
+	}
+	if userID := c.Get("X-User-ID"); userID != "" {
+		return userID
+	}
+	return "admin"
+}
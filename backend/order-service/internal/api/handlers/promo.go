@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/api/requests"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/services"
+	"github.com/tonysanin/brobar/pkg/response"
+)
+
+type PromoHandler struct {
+	service *services.PromoService
+}
+
+func NewPromoHandler(service *services.PromoService) *PromoHandler {
+	return &PromoHandler{service: service}
+}
+
+func (h *PromoHandler) GetPromoCodes(c fiber.Ctx) error {
+	promos, err := h.service.GetAllPromoCodes(c.Context())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, promos)
+}
+
+func (h *PromoHandler) GetPromoCode(c fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid promo code id"))
+	}
+
+	promo, err := h.service.GetPromoCodeById(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, customerrors.PromoCodeNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, promo)
+}
+
+func (h *PromoHandler) CreatePromoCode(c fiber.Ctx) error {
+	var req requests.PromoCodeRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	promo := req.ToModel()
+	if err := h.service.CreatePromoCode(c.Context(), promo); err != nil {
+		if errors.Is(err, customerrors.PromoCodeAlreadyExists) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, promo)
+}
+
+func (h *PromoHandler) UpdatePromoCode(c fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid promo code id"))
+	}
+
+	var req requests.PromoCodeRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	promo := req.ToModel()
+	promo.ID = id
+
+	if err := h.service.UpdatePromoCode(c.Context(), promo); err != nil {
+		if errors.Is(err, customerrors.PromoCodeNotFound) {
+			return response.NotFound(c)
+		}
+		if errors.Is(err, customerrors.PromoCodeAlreadyExists) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, promo)
+}
+
+func (h *PromoHandler) DeletePromoCode(c fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid promo code id"))
+	}
+
+	if err := h.service.DeletePromoCode(c.Context(), id); err != nil {
+		if errors.Is(err, customerrors.PromoCodeNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, nil)
+}
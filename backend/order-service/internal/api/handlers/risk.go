@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/api/requests"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/services"
+	"github.com/tonysanin/brobar/pkg/response"
+)
+
+type RiskHandler struct {
+	service *services.RiskService
+}
+
+func NewRiskHandler(service *services.RiskService) *RiskHandler {
+	return &RiskHandler{service: service}
+}
+
+func (h *RiskHandler) GetPhoneFlags(c fiber.Ctx) error {
+	flags, err := h.service.GetAllFlags(c.Context())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, flags)
+}
+
+// GetPhoneRisk returns the flag and order history of a phone
+func (h *RiskHandler) GetPhoneRisk(c fiber.Ctx) error {
+	risk, err := h.service.GetPhoneRisk(c.Context(), c.Params("phone"), uuid.Nil)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPhone) {
+			return response.BadRequest(c, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, risk)
+}
+
+// FlagPhone makes the phone pay online or blocks it, replacing an earlier flag
+func (h *RiskHandler) FlagPhone(c fiber.Ctx) error {
+	var req requests.PhoneFlagRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	flag := &models.PhoneFlag{
+		Phone:     c.Params("phone"),
+		Action:    models.RiskAction(req.Action),
+		FlaggedBy: changedBy(c),
+	}
+	if req.FlaggedBy != "" && c.Get("X-User-ID") == "" {
+		flag.FlaggedBy = req.FlaggedBy
+	}
+	if req.Reason != "" {
+		flag.Reason = &req.Reason
+	}
+
+	if err := h.service.FlagPhone(c.Context(), flag); err != nil {
+		if errors.Is(err, services.ErrInvalidPhone) {
+			return response.BadRequest(c, err)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, flag)
+}
+
+func (h *RiskHandler) UnflagPhone(c fiber.Ctx) error {
+	if err := h.service.UnflagPhone(c.Context(), c.Params("phone")); err != nil {
+		if errors.Is(err, services.ErrInvalidPhone) {
+			return response.BadRequest(c, err)
+		}
+		if errors.Is(err, customerrors.PhoneFlagNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, nil)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/response"
+)
+
+// GetUserOrders lists the signed-in customer's order history
+func (h *OrderHandler) GetUserOrders(c fiber.Ctx) error {
+	userID := requestUserID(c)
+	if userID == nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	orders, totalCount, err := h.service.GetUserOrders(c.Context(), *userID, limit, (page-1)*limit)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	resp := response.PaginatedResponse[*models.Order]{
+		Data: orders,
+		Pagination: response.Pagination{
+			TotalCount: totalCount,
+			Page:       page,
+			Limit:      limit,
+			OrderBy:    "created_at",
+			OrderDir:   "desc",
+		},
+	}
+
+	return response.Success(c, resp)
+}
+
+// GetUserOrder returns one order of the signed-in customer
+func (h *OrderHandler) GetUserOrder(c fiber.Ctx) error {
+	userID := requestUserID(c)
+	if userID == nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order id"))
+	}
+
+	order, err := h.service.GetUserOrder(c.Context(), *userID, id)
+	if err != nil {
+		if errors.Is(err, customerrors.OrderNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, order)
+}
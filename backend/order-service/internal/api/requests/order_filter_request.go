@@ -0,0 +1,161 @@
+package requests
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// OrderFilterRequest - admin order list filters, taken from the query string.
+// Statuses and delivery types are comma-separated, dates are RFC3339 or YYYY-MM-DD
+// (a date-only "to" includes the whole day).
+type OrderFilterRequest struct {
+	Status        string
+	DeliveryType  string
+	PaymentMethod string
+	Zone          string
+	CreatedFrom   string
+	CreatedTo     string
+	TimeFrom      string
+	TimeTo        string
+	Phone         string
+	Name          string
+	TotalMin      string
+	TotalMax      string
+	Scheduled     string
+}
+
+func (r OrderFilterRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.By(eachIn(
+			string(models.StatusPending),
+			string(models.StatusPaid),
+			string(models.StatusShipping),
+			string(models.StatusCompleted),
+			string(models.StatusCancelled),
+		))),
+		validation.Field(&r.DeliveryType, validation.By(eachIn(
+			string(models.DeliveryTypeDelivery),
+			string(models.DeliveryTypePickup),
+			string(models.DeliveryTypeDine),
+		))),
+		validation.Field(&r.PaymentMethod, validation.In("online", "cash", "bank")),
+		validation.Field(&r.Zone, validation.Length(0, 255)),
+		validation.Field(&r.Phone, validation.Length(0, 32)),
+		validation.Field(&r.Name, validation.Length(0, 255)),
+		validation.Field(&r.Scheduled, validation.In("true", "false")),
+	)
+}
+
+// ToModel parses the filter values, date-only values are taken in the given location
+func (r OrderFilterRequest) ToModel(loc *time.Location) (models.OrderFilter, error) {
+	filter := models.OrderFilter{
+		PaymentMethod: r.PaymentMethod,
+		Zone:          strings.TrimSpace(r.Zone),
+		Phone:         strings.TrimSpace(r.Phone),
+		Name:          strings.TrimSpace(r.Name),
+	}
+
+	for _, status := range splitList(r.Status) {
+		filter.StatusIDs = append(filter.StatusIDs, models.Status(status))
+	}
+	for _, deliveryType := range splitList(r.DeliveryType) {
+		filter.DeliveryTypeIDs = append(filter.DeliveryTypeIDs, models.DeliveryType(deliveryType))
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseFilterTime("created_from", r.CreatedFrom, loc, false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseFilterTime("created_to", r.CreatedTo, loc, true); err != nil {
+		return filter, err
+	}
+	if filter.TimeFrom, err = parseFilterTime("time_from", r.TimeFrom, loc, false); err != nil {
+		return filter, err
+	}
+	if filter.TimeTo, err = parseFilterTime("time_to", r.TimeTo, loc, true); err != nil {
+		return filter, err
+	}
+	if filter.TotalMin, err = parseFilterAmount("total_min", r.TotalMin); err != nil {
+		return filter, err
+	}
+	if filter.TotalMax, err = parseFilterAmount("total_max", r.TotalMax); err != nil {
+		return filter, err
+	}
+	if r.Scheduled != "" {
+		scheduled := r.Scheduled == "true"
+		filter.Scheduled = &scheduled
+	}
+
+	return filter, nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// eachIn checks every value of a comma-separated list against the allowed ones
+func eachIn(allowed ...string) validation.RuleFunc {
+	return func(value interface{}) error {
+		str, _ := value.(string)
+		for _, part := range splitList(str) {
+			found := false
+			for _, a := range allowed {
+				if part == a {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("invalid value %q", part)
+			}
+		}
+		return nil
+	}
+}
+
+// parseFilterTime parses a range bound; a date-only upper bound moves to the start of the next day
+func parseFilterTime(field, value string, loc *time.Location, upper bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.ParseInLocation(filterDateLayout, value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: expected RFC3339 or YYYY-MM-DD", field)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func parseFilterAmount(field, value string) (*float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("%s: expected a non-negative number", field)
+	}
+	return &amount, nil
+}
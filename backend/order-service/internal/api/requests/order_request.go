@@ -31,20 +31,53 @@ type CreateOrderRequest struct {
 	PaymentMethod string `json:"payment_method"` // "online" | "cash"
 	Cutlery       int    `json:"cutlery,omitempty"`
 	PromoCode     string `json:"promo_code,omitempty"`
+	LoyaltyPoints int    `json:"loyalty_points,omitempty"` // signed-in customers only
 	Wishes        string `json:"wishes,omitempty"`
 
 	// Items (minimal - only IDs and quantities)
 	Items []OrderItemRequest `json:"items"`
 
-	// Client-calculated total for validation
+	// Client-calculated total for validation, not needed when a quote token is sent
 	ClientTotal float64 `json:"client_total"`
+	QuoteToken  string  `json:"quote_token,omitempty"`
+}
+
+// QuoteOrderRequest - cart data needed to price an order
+type QuoteOrderRequest struct {
+	Phone          string             `json:"phone,omitempty"` // per-phone promo limits
+	DeliveryTypeID string             `json:"delivery_type_id"`
+	DeliveryDoor   bool               `json:"delivery_door,omitempty"`
+	Coords         string             `json:"coords,omitempty"`
+	PromoCode      string             `json:"promo_code,omitempty"`
+	LoyaltyPoints  int                `json:"loyalty_points,omitempty"`
+	Items          []OrderItemRequest `json:"items"`
+}
+
+func (r QuoteOrderRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Phone, validator.IsPhone, validation.Length(6, 32)),
+		validation.Field(&r.DeliveryTypeID, validation.Required, validation.In(
+			string(models.DeliveryTypeDelivery),
+			string(models.DeliveryTypePickup),
+			string(models.DeliveryTypeDine),
+		)),
+		validation.Field(&r.LoyaltyPoints, validation.Min(0)),
+		validation.Field(&r.Items, validation.Required, validation.Length(1, 100)),
+	)
 }
 
 // OrderItemRequest - minimal item data from frontend
 type OrderItemRequest struct {
-	ProductID          uuid.UUID  `json:"product_id"`
-	ProductVariationID *uuid.UUID `json:"product_variation_id,omitempty"`
-	Quantity           int        `json:"quantity"`
+	ProductID          uuid.UUID                   `json:"product_id"`
+	ProductVariationID *uuid.UUID                  `json:"product_variation_id,omitempty"` // legacy, prefer variations
+	Variations         []OrderItemVariationRequest `json:"variations,omitempty"`
+	Quantity           int                         `json:"quantity"`
+}
+
+// OrderItemVariationRequest - a selected variation of a cart line
+type OrderItemVariationRequest struct {
+	VariationID uuid.UUID `json:"variation_id"`
+	Quantity    int       `json:"quantity"`
 }
 
 func (r CreateOrderRequest) Validate() error {
@@ -58,18 +91,27 @@ func (r CreateOrderRequest) Validate() error {
 		)),
 		validation.Field(&r.PaymentMethod, validation.Required, validation.In("online", "cash", "bank")),
 		validation.Field(&r.Time, validation.Required),
+		validation.Field(&r.LoyaltyPoints, validation.Min(0)),
 		validation.Field(&r.Items, validation.Required, validation.Length(1, 100)),
-		validation.Field(&r.ClientTotal, validation.Required, validator.IsNonNegative),
+		validation.Field(&r.ClientTotal, validation.When(r.QuoteToken == "", validation.Required), validator.IsNonNegative),
 	)
 }
 
 func (r OrderItemRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ProductID, validation.Required, validator.IsUUID),
+		validation.Field(&r.Variations, validation.Length(0, 50)),
 		validation.Field(&r.Quantity, validation.Required, validation.Min(1)),
 	)
 }
 
+func (r OrderItemVariationRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.VariationID, validation.Required, validator.IsUUID),
+		validation.Field(&r.Quantity, validation.Required, validation.Min(1), validation.Max(50)),
+	)
+}
+
 // UpdateOrderRequest - admin update (full data)
 type UpdateOrderRequest struct {
 	ID             uuid.UUID                `json:"-"`
@@ -101,7 +143,7 @@ func (r UpdateOrderRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Name, validation.Required, validation.Length(2, 100)),
 		validation.Field(&r.Address, validation.Required, validation.Length(5, 256)),
-		validation.Field(&r.Phone, validation.Required, validator.IsPhone, validation.Length(6, 32)),
+		validation.Field(&r.Phone, validation.Required, validation.Length(6, 32)), // a changed phone is checked by the service
 		validation.Field(&r.StatusID, validation.In(
 			string(models.StatusPending),
 			string(models.StatusPaid),
@@ -132,3 +174,60 @@ func (r UpdateOrderItemRequest) Validate() error {
 		validation.Field(&r.ProductVariationName, validation.Length(1, 255)),
 	)
 }
+
+// UpdateOrderStatusRequest - admin status change
+type UpdateOrderStatusRequest struct {
+	StatusID string `json:"status_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (r UpdateOrderStatusRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.StatusID, validation.Required, validation.In(
+			string(models.StatusPending),
+			string(models.StatusPaid),
+			string(models.StatusShipping),
+			string(models.StatusCompleted),
+			string(models.StatusCancelled))),
+		validation.Field(&r.Reason, validation.Length(0, 1024)),
+	)
+}
+
+// CancelOrderRequest - admin cancellation with a reason kept in the status history
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+	NoShow bool   `json:"no_show,omitempty"` // the customer did not take the order
+}
+
+func (r CancelOrderRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Reason, validation.Required, validation.Length(1, 1024)),
+	)
+}
+
+// CourierStatusRequest - delivery state reported by ontaxi-service
+type CourierStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func (r CourierStatusRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status, validation.Required, validation.In(models.CourierDispatched)),
+	)
+}
+
+// AvailableSlotsRequest - checkout time slots of a date, taken from the query string
+type AvailableSlotsRequest struct {
+	Date         string
+	DeliveryType string
+}
+
+func (r AvailableSlotsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Date, validation.Required, validation.Date(filterDateLayout)),
+		validation.Field(&r.DeliveryType, validation.Required, validation.In(
+			string(models.DeliveryTypeDelivery),
+			string(models.DeliveryTypePickup),
+			string(models.DeliveryTypeDine))),
+	)
+}
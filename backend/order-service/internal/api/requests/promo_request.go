@@ -0,0 +1,79 @@
+package requests
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+// PromoCodeRequest - admin create/update of a promo code
+type PromoCodeRequest struct {
+	Code            string      `json:"code"`
+	DiscountType    string      `json:"discount_type"` // "percent" | "fixed"
+	DiscountValue   float64     `json:"discount_value"`
+	MinCart         float64     `json:"min_cart"`
+	StartsAt        *time.Time  `json:"starts_at,omitempty"`
+	EndsAt          *time.Time  `json:"ends_at,omitempty"`
+	MaxUses         *int        `json:"max_uses,omitempty"`
+	MaxUsesPerPhone *int        `json:"max_uses_per_phone,omitempty"`
+	ProductIDs      []uuid.UUID `json:"product_ids,omitempty"`
+	CategoryIDs     []uuid.UUID `json:"category_ids,omitempty"`
+	Active          bool        `json:"active"`
+}
+
+func (r PromoCodeRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Code, validation.Required, validation.Length(2, 64)),
+		validation.Field(&r.DiscountType, validation.Required, validation.In(
+			string(models.PromoDiscountPercent),
+			string(models.PromoDiscountFixed),
+		)),
+		validation.Field(&r.DiscountValue, validation.Required, validator.IsNonNegative, validation.By(func(value interface{}) error {
+			if r.DiscountType == string(models.PromoDiscountPercent) && r.DiscountValue > 100 {
+				return errors.New("percent discount must be <= 100")
+			}
+			return nil
+		})),
+		validation.Field(&r.MinCart, validator.IsNonNegative),
+		validation.Field(&r.EndsAt, validation.By(func(value interface{}) error {
+			if r.StartsAt != nil && r.EndsAt != nil && r.EndsAt.Before(*r.StartsAt) {
+				return errors.New("ends_at must be after starts_at")
+			}
+			return nil
+		})),
+		validation.Field(&r.MaxUses, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&r.MaxUsesPerPhone, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&r.ProductIDs, validation.Each(validator.IsUUID)),
+		validation.Field(&r.CategoryIDs, validation.Each(validator.IsUUID)),
+	)
+}
+
+// ToModel maps the request onto a promo code model
+func (r PromoCodeRequest) ToModel() *models.PromoCode {
+	promo := &models.PromoCode{
+		Code:            r.Code,
+		DiscountType:    models.PromoDiscountType(r.DiscountType),
+		DiscountValue:   r.DiscountValue,
+		MinCart:         r.MinCart,
+		StartsAt:        r.StartsAt,
+		EndsAt:          r.EndsAt,
+		MaxUses:         r.MaxUses,
+		MaxUsesPerPhone: r.MaxUsesPerPhone,
+		ProductIDs:      make([]string, len(r.ProductIDs)),
+		CategoryIDs:     make([]string, len(r.CategoryIDs)),
+		Active:          r.Active,
+	}
+
+	for i, id := range r.ProductIDs {
+		promo.ProductIDs[i] = id.String()
+	}
+	for i, id := range r.CategoryIDs {
+		promo.CategoryIDs[i] = id.String()
+	}
+
+	return promo
+}
@@ -0,0 +1,24 @@
+package requests
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// PhoneFlagRequest - admin or Telegram bot flag of a customer phone
+type PhoneFlagRequest struct {
+	Action    string `json:"action"` // "online_only" | "block"
+	Reason    string `json:"reason,omitempty"`
+	FlaggedBy string `json:"flagged_by,omitempty"` // internal callers without a signed-in admin
+}
+
+func (r PhoneFlagRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Action, validation.Required, validation.In(
+			string(models.RiskOnlineOnly),
+			string(models.RiskBlock),
+		)),
+		validation.Field(&r.Reason, validation.Length(0, 1024)),
+		validation.Field(&r.FlaggedBy, validation.Length(0, 255)),
+	)
+}
@@ -9,13 +9,20 @@ import (
 )
 
 type Server struct {
-	app          *fiber.App
-	orderService *services.OrderService
-	orderHandler *handlers.OrderHandler
+	app              *fiber.App
+	orderService     *services.OrderService
+	orderHandler     *handlers.OrderHandler
+	promoHandler     *handlers.PromoHandler
+	riskHandler      *handlers.RiskHandler
+	analyticsHandler *handlers.AnalyticsHandler
 }
 
 func NewServer(
 	orderService *services.OrderService,
+	promoService *services.PromoService,
+	riskService *services.RiskService,
+	idempotencyService *services.IdempotencyService,
+	analyticsService *services.AnalyticsService,
 ) *Server {
 	s := &Server{
 		app: fiber.New(fiber.Config{
@@ -28,7 +35,10 @@ func NewServer(
 		Level: compress.LevelBestSpeed,
 	}))
 
-	s.orderHandler = handlers.NewOrderHandler(orderService)
+	s.orderHandler = handlers.NewOrderHandler(orderService, idempotencyService)
+	s.promoHandler = handlers.NewPromoHandler(promoService)
+	s.riskHandler = handlers.NewRiskHandler(riskService)
+	s.analyticsHandler = handlers.NewAnalyticsHandler(analyticsService, orderService)
 
 	s.SetupRoutes()
 
@@ -44,11 +54,42 @@ func (s *Server) SetupRoutes() {
 
 	orderGroup := s.app.Group("/orders")
 	orderGroup.Get("/", s.orderHandler.GetOrders)
+	orderGroup.Get("/export", s.orderHandler.ExportOrders)
+	orderGroup.Get("/slots", s.orderHandler.GetAvailableSlots)
+	orderGroup.Get("/track/:token", s.orderHandler.TrackOrder)
 	orderGroup.Get("/:id", s.orderHandler.GetOrder)
 	orderGroup.Post("/", s.orderHandler.CreateOrder)
+	orderGroup.Post("/quote", s.orderHandler.QuoteOrder)
 	orderGroup.Put("/:id", s.orderHandler.UpdateOrder)
 	orderGroup.Delete("/:id", s.orderHandler.DeleteOrder)
+	orderGroup.Patch("/:id/status", s.orderHandler.UpdateOrderStatus)
+	orderGroup.Post("/:id/cancel", s.orderHandler.CancelOrder)
+	orderGroup.Get("/:id/status-history", s.orderHandler.GetOrderStatusHistory)
 	orderGroup.Post("/:id/syrve-notified", s.orderHandler.MarkSyrveNotified)
+	orderGroup.Post("/:id/courier", s.orderHandler.SetCourierStatus)
+	orderGroup.Post("/:id/release", s.orderHandler.ReleaseScheduledOrder)
+
+	userOrderGroup := s.app.Group("/user/orders")
+	userOrderGroup.Get("/", s.orderHandler.GetUserOrders)
+	userOrderGroup.Get("/:id", s.orderHandler.GetUserOrder)
+
+	promoGroup := s.app.Group("/promo-codes")
+	promoGroup.Get("/", s.promoHandler.GetPromoCodes)
+	promoGroup.Get("/:id", s.promoHandler.GetPromoCode)
+	promoGroup.Post("/", s.promoHandler.CreatePromoCode)
+	promoGroup.Put("/:id", s.promoHandler.UpdatePromoCode)
+	promoGroup.Delete("/:id", s.promoHandler.DeletePromoCode)
+
+	riskGroup := s.app.Group("/risk/phones")
+	riskGroup.Get("/", s.riskHandler.GetPhoneFlags)
+	riskGroup.Get("/:phone", s.riskHandler.GetPhoneRisk)
+	riskGroup.Put("/:phone", s.riskHandler.FlagPhone)
+	riskGroup.Delete("/:phone", s.riskHandler.UnflagPhone)
+
+	analyticsGroup := s.app.Group("/analytics")
+	analyticsGroup.Get("/sales", s.analyticsHandler.GetSales)
+	analyticsGroup.Get("/top-products", s.analyticsHandler.GetTopProducts)
+	analyticsGroup.Get("/top-variations", s.analyticsHandler.GetTopVariations)
 }
 
 func (s *Server) Listen(address string) error {
@@ -0,0 +1,73 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Geometry is a GeoJSON Polygon or MultiPolygon, positions are [lng, lat]
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+
+	polygons [][][][2]float64
+}
+
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	type raw Geometry
+	if err := json.Unmarshal(data, (*raw)(g)); err != nil {
+		return err
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var polygon [][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		g.polygons = [][][][2]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &g.polygons); err != nil {
+			return fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported geometry type: %s", g.Type)
+	}
+
+	return nil
+}
+
+// Contains reports whether the point is inside one of the polygons and outside its holes
+func (g *Geometry) Contains(lat, lng float64) bool {
+	for _, polygon := range g.polygons {
+		if len(polygon) == 0 || !ringContains(polygon[0], lat, lng) {
+			continue
+		}
+
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains is the even-odd ray casting test, the ring may be closed or not
+func ringContains(ring [][2]float64, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
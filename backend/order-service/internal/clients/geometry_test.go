@@ -0,0 +1,50 @@
+package clients
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeometryContains(t *testing.T) {
+	// A 10x10 square with a 2x2 hole in the middle, positions are [lng, lat]
+	polygon := `{"type": "Polygon", "coordinates": [
+		[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+		[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+	]}`
+	multiPolygon := `{"type": "MultiPolygon", "coordinates": [
+		[[[0, 0], [2, 0], [2, 2], [0, 2]]],
+		[[[20, 20], [22, 20], [22, 22], [20, 22]]]
+	]}`
+
+	tests := []struct {
+		name     string
+		geometry string
+		lat, lng float64
+		want     bool
+	}{
+		{"inside", polygon, 2, 2, true},
+		{"outside", polygon, 11, 5, false},
+		{"in the hole", polygon, 5, 5, false},
+		{"between the hole and the edge", polygon, 5, 7, true},
+		{"lat and lng not swapped", polygon, 5, -1, false},
+		{"first polygon, open ring", multiPolygon, 1, 1, true},
+		{"second polygon", multiPolygon, 21, 21, true},
+		{"between polygons", multiPolygon, 10, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g Geometry
+			require.NoError(t, json.Unmarshal([]byte(tt.geometry), &g))
+			assert.Equal(t, tt.want, g.Contains(tt.lat, tt.lng))
+		})
+	}
+}
+
+func TestGeometryUnmarshalRejectsOtherTypes(t *testing.T) {
+	var g Geometry
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "Point", "coordinates": [1, 2]}`), &g))
+}
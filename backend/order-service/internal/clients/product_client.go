@@ -13,6 +13,8 @@ import (
 type ProductClient struct {
 	baseURL    string
 	httpClient *http.Client
+
+	products *ttlCache[Product]
 }
 
 func NewProductClient() *ProductClient {
@@ -21,6 +23,7 @@ func NewProductClient() *ProductClient {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		products: newTTLCache[Product](productCacheTTL),
 	}
 }
 
@@ -33,10 +36,14 @@ type ProductResponse struct {
 type Product struct {
 	ID         uuid.UUID `json:"id"`
 	ExternalID string    `json:"external_id"`
+	CategoryID uuid.UUID `json:"category_id"`
 	Name       string    `json:"name"`
 	Price      float64   `json:"price"`
 	Weight     float64   `json:"weight"`
 	Stock      *float64  `json:"stock"`
+
+	// Filled by ResolveProducts only
+	VariationGroups []VariationGroup `json:"variation_groups"`
 }
 
 // Variation response
@@ -46,10 +53,13 @@ type VariationResponse struct {
 }
 
 type Variation struct {
-	ID         uuid.UUID `json:"id"`
-	GroupID    uuid.UUID `json:"group_id"`
-	ExternalID string    `json:"external_id"`
-	Name       string    `json:"name"`
+	ID           uuid.UUID `json:"id"`
+	GroupID      uuid.UUID `json:"group_id"`
+	ExternalID   string    `json:"external_id"`
+	Name         string    `json:"name"`
+	DefaultValue *int      `json:"default_value"`
+	Show         bool      `json:"show"`
+	Price        float64   `json:"price"`
 }
 
 // VariationGroup response
@@ -59,9 +69,12 @@ type VariationGroupResponse struct {
 }
 
 type VariationGroup struct {
-	ID        uuid.UUID `json:"id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Name      string    `json:"name"`
+	ID         uuid.UUID   `json:"id"`
+	ProductID  uuid.UUID   `json:"product_id"`
+	Name       string      `json:"name"`
+	ExternalID string      `json:"external_id"`
+	Required   bool        `json:"required"`
+	Variations []Variation `json:"variations"`
 }
 
 func (c *ProductClient) GetProduct(productID uuid.UUID) (*Product, error) {
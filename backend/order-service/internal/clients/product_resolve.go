@@ -0,0 +1,107 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// productCacheTTL keeps resolved catalog data just long enough to cover a quote and the order that follows
+const productCacheTTL = 30 * time.Second
+
+type resolveResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Products []Product `json:"products"`
+	} `json:"data"`
+}
+
+type cacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// ttlCache is a small in-memory cache, entries are dropped lazily on read
+type ttlCache[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[uuid.UUID]cacheEntry[T]
+}
+
+func newTTLCache[T any](ttl time.Duration) *ttlCache[T] {
+	return &ttlCache[T]{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cacheEntry[T]),
+	}
+}
+
+func (c *ttlCache[T]) get(id uuid.UUID) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, id)
+		var zero T
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache[T]) set(id uuid.UUID, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[id] = cacheEntry[T]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// ResolveProducts returns products with their variation groups in one request,
+// serving what it can from cache. Unknown IDs are simply absent from the result.
+func (c *ProductClient) ResolveProducts(productIDs []uuid.UUID) (map[uuid.UUID]Product, error) {
+	resolved := make(map[uuid.UUID]Product)
+
+	var missing []uuid.UUID
+	for _, id := range productIDs {
+		if product, ok := c.products.get(id); ok {
+			resolved[id] = product
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+
+	body, err := json.Marshal(map[string][]uuid.UUID{"product_ids": missing})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resolve request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/products/resolve", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve products: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d on resolve", resp.StatusCode)
+	}
+
+	var resolveResp resolveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&resolveResp); err != nil {
+		return nil, fmt.Errorf("failed to decode resolve response: %w", err)
+	}
+
+	for _, product := range resolveResp.Data.Products {
+		c.products.set(product.ID, product)
+		resolved[product.ID] = product
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ErrInsufficientStock is returned when product-service cannot reserve the requested quantity
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+type StockItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  float64   `json:"quantity"`
+}
+
+type reserveStockRequest struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	Items   []StockItem `json:"items"`
+}
+
+type stockErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// ReserveStock atomically reserves stock for all order items in product-service
+func (c *ProductClient) ReserveStock(orderID uuid.UUID, items []StockItem) error {
+	body, err := json.Marshal(reserveStockRequest{OrderID: orderID, Items: items})
+	if err != nil {
+		return fmt.Errorf("failed to encode reserve request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(fmt.Sprintf("%s/stock/reservations", c.baseURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		var errResp stockErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("%w: %s", ErrInsufficientStock, errResp.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to reserve stock: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CommitStock finalizes the order's reservation once the order is confirmed
+func (c *ProductClient) CommitStock(orderID uuid.UUID) error {
+	return c.postReservationAction(orderID, "commit")
+}
+
+// ReleaseStock returns the order's reserved stock
+func (c *ProductClient) ReleaseStock(orderID uuid.UUID) error {
+	return c.postReservationAction(orderID, "release")
+}
+
+func (c *ProductClient) postReservationAction(orderID uuid.UUID, action string) error {
+	resp, err := c.httpClient.Post(fmt.Sprintf("%s/stock/reservations/%s/%s", c.baseURL, orderID.String(), action), "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s stock: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	// Orders created before reservations existed have nothing to commit or release
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to %s stock: status %d", action, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/helpers"
+)
+
+// ErrInsufficientPoints is returned when the user's loyalty balance cannot cover the redemption
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+// UserClient talks to the loyalty ledger of user-service
+type UserClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewUserClient() *UserClient {
+	return &UserClient{
+		baseURL: helpers.GetEnv("USER_SERVICE_URL", "http://user-service-dev:3002"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+type loyaltyRequest struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Points  int       `json:"points,omitempty"`
+}
+
+type loyaltyErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// EarnPoints credits the points of a completed order
+func (c *UserClient) EarnPoints(userID, orderID uuid.UUID, points int) error {
+	return c.postLoyalty(userID, "earn", loyaltyRequest{OrderID: orderID, Points: points})
+}
+
+// RedeemPoints debits the points paid for an order
+func (c *UserClient) RedeemPoints(userID, orderID uuid.UUID, points int) error {
+	return c.postLoyalty(userID, "redeem", loyaltyRequest{OrderID: orderID, Points: points})
+}
+
+// ReversePoints gives back the redeemed and takes away the earned points of an order
+func (c *UserClient) ReversePoints(userID, orderID uuid.UUID) error {
+	return c.postLoyalty(userID, "reverse", loyaltyRequest{OrderID: orderID})
+}
+
+func (c *UserClient) postLoyalty(userID uuid.UUID, action string, req loyaltyRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode loyalty request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(fmt.Sprintf("%s/loyalty/%s/%s", c.baseURL, userID.String(), action), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to %s loyalty points: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		var errResp loyaltyErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("%w: %s", ErrInsufficientPoints, errResp.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to %s loyalty points: status %d", action, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -51,7 +51,7 @@ type TimeData struct {
 	DayNumber int    `json:"day_number"`
 }
 
-// Working hours structures
+// Working hours structures. A shift whose end is not after its start ends the next day.
 type DaySchedule struct {
 	Start  string `json:"start"`
 	End    string `json:"end"`
@@ -61,6 +61,27 @@ type DaySchedule struct {
 type WorkingHours struct {
 	Delivery map[string]DaySchedule `json:"delivery"`
 	Pickup   map[string]DaySchedule `json:"pickup"`
+
+	// Overrides come from the "working_hours_overrides" setting
+	Overrides []ScheduleOverride `json:"-"`
+}
+
+// ScheduleOverride replaces the weekday hours on a specific date (YYYY-MM-DD),
+// for all delivery types
+type ScheduleOverride struct {
+	Date string `json:"date"`
+	DaySchedule
+	Reason string `json:"reason,omitempty"`
+}
+
+// Override returns the override for the date, if there is one
+func (wh *WorkingHours) Override(date string) (ScheduleOverride, bool) {
+	for _, override := range wh.Overrides {
+		if override.Date == date {
+			return override, true
+		}
+	}
+	return ScheduleOverride{}, false
 }
 
 func (c *WebClient) GetSettings() ([]Setting, error) {
@@ -99,17 +120,31 @@ func (c *WebClient) GetWorkingHours() (*WorkingHours, error) {
 		return nil, err
 	}
 
+	var wh *WorkingHours
+	var overrides []ScheduleOverride
 	for _, s := range settings {
-		if s.Key == "working_hours" {
-			var wh WorkingHours
-			if err := json.Unmarshal([]byte(s.Value), &wh); err != nil {
+		switch s.Key {
+		case "working_hours":
+			wh = &WorkingHours{}
+			if err := json.Unmarshal([]byte(s.Value), wh); err != nil {
 				return nil, fmt.Errorf("failed to parse working hours: %w", err)
 			}
-			return &wh, nil
+		case "working_hours_overrides":
+			if s.Value == "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(s.Value), &overrides); err != nil {
+				return nil, fmt.Errorf("failed to parse working hours overrides: %w", err)
+			}
 		}
 	}
 
-	return nil, fmt.Errorf("working_hours setting not found")
+	if wh == nil {
+		return nil, fmt.Errorf("working_hours setting not found")
+	}
+	wh.Overrides = overrides
+
+	return wh, nil
 }
 
 func (c *WebClient) GetDeliveryDoorPrice() (float64, error) {
@@ -131,6 +166,41 @@ func (c *WebClient) GetDeliveryDoorPrice() (float64, error) {
 	return 50.0, nil // Default
 }
 
+// SalesPause describes the "sales_paused" flag together with its optional reason and resume time
+type SalesPause struct {
+	Paused   bool
+	Reason   string
+	ResumeAt *time.Time
+}
+
+func (c *WebClient) GetSalesPause(loc *time.Location) (*SalesPause, error) {
+	settings, err := c.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	pause := &SalesPause{}
+	for _, s := range settings {
+		switch s.Key {
+		case "sales_paused":
+			pause.Paused = s.Value == "true"
+		case "sales_paused_reason":
+			pause.Reason = s.Value
+		case "sales_paused_until":
+			if s.Value == "" {
+				continue
+			}
+			resumeAt, err := time.ParseInLocation("2006-01-02 15:04", s.Value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse sales_paused_until: %w", err)
+			}
+			pause.ResumeAt = &resumeAt
+		}
+	}
+
+	return pause, nil
+}
+
 func (c *WebClient) GetDeliveryZones() ([]DeliveryZone, error) {
 	settings, err := c.GetSettings()
 	if err != nil {
@@ -150,12 +220,119 @@ func (c *WebClient) GetDeliveryZones() ([]DeliveryZone, error) {
 	return nil, fmt.Errorf("delivery_zones setting not found")
 }
 
+// SlotLimit caps a time slot, zero values mean unlimited
+type SlotLimit struct {
+	MaxOrders int `json:"max_orders"`
+	MaxItems  int `json:"max_items"`
+}
+
+func (l SlotLimit) Unlimited() bool {
+	return l.MaxOrders <= 0 && l.MaxItems <= 0
+}
+
+// SlotCapacity is the "slot_capacity" setting: slot length and limits per delivery type
+type SlotCapacity struct {
+	SlotMinutes int       `json:"slot_minutes"`
+	Delivery    SlotLimit `json:"delivery"`
+	Pickup      SlotLimit `json:"pickup"`
+	Dine        SlotLimit `json:"dine"`
+}
+
+func (c *SlotCapacity) Limit(deliveryType string) SlotLimit {
+	switch deliveryType {
+	case "pickup":
+		return c.Pickup
+	case "dine":
+		return c.Dine
+	default:
+		return c.Delivery
+	}
+}
+
+const defaultSlotMinutes = 15
+
+// GetSlotCapacity returns the time slot limits, without the setting slots are unlimited
+func (c *WebClient) GetSlotCapacity() (*SlotCapacity, error) {
+	settings, err := c.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := &SlotCapacity{SlotMinutes: defaultSlotMinutes}
+	for _, s := range settings {
+		if s.Key == "slot_capacity" {
+			if err := json.Unmarshal([]byte(s.Value), capacity); err != nil {
+				return nil, fmt.Errorf("failed to parse slot capacity: %w", err)
+			}
+		}
+	}
+	if capacity.SlotMinutes <= 0 {
+		capacity.SlotMinutes = defaultSlotMinutes
+	}
+
+	return capacity, nil
+}
+
+// LoyaltySettings is the "loyalty" setting. One point is worth one hryvnia.
+type LoyaltySettings struct {
+	Enabled          bool    `json:"enabled"`
+	EarnRate         float64 `json:"earn_rate"`          // points per hryvnia paid for the items
+	MaxRedeemPercent float64 `json:"max_redeem_percent"` // share of the discounted items total
+}
+
+// GetLoyaltySettings returns the loyalty program rules, without the setting the program is off
+func (c *WebClient) GetLoyaltySettings() (*LoyaltySettings, error) {
+	settings, err := c.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	loyalty := &LoyaltySettings{}
+	for _, s := range settings {
+		if s.Key == "loyalty" {
+			if err := json.Unmarshal([]byte(s.Value), loyalty); err != nil {
+				return nil, fmt.Errorf("failed to parse loyalty settings: %w", err)
+			}
+		}
+	}
+
+	return loyalty, nil
+}
+
+// DeliveryZone is either a GeoJSON polygon or, for older zones, a ring of Radius/InnerRadius
+// km around the zone center. Overlapping zones are resolved by Priority.
 type DeliveryZone struct {
-	Name           string  `json:"name"`
-	Price          float64 `json:"price"`
-	FreeOrderPrice float64 `json:"freeOrderPrice"`
-	Radius         float64 `json:"radius"`
-	InnerRadius    float64 `json:"innerRadius"`
+	Name           string    `json:"name"`
+	Price          float64   `json:"price"`
+	FreeOrderPrice float64   `json:"freeOrderPrice"`
+	Radius         float64   `json:"radius"`
+	InnerRadius    float64   `json:"innerRadius"`
+	Geometry       *Geometry `json:"geometry,omitempty"`
+	Priority       int       `json:"priority,omitempty"`
+
+	// Zone rules, zero values fall back to the general ones
+	MinOrderAmount float64  `json:"minOrderAmount,omitempty"` // discounted items total
+	DoorPrice      *float64 `json:"doorPrice,omitempty"`      // overrides delivery_door_price
+	PaymentMethods []string `json:"paymentMethods,omitempty"` // normalized: "online", "cash"
+	PrepMinutes    int      `json:"prepMinutes,omitempty"`    // added to the kitchen lead time
+}
+
+// AllowsPaymentMethod checks a normalized payment method against the zone's list
+func (z *DeliveryZone) AllowsPaymentMethod(method string) bool {
+	if len(z.PaymentMethods) == 0 {
+		return true
+	}
+	for _, allowed := range z.PaymentMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// PrepTime is the extra time the kitchen needs for orders to the zone
+func (z *DeliveryZone) PrepTime() time.Duration {
+	return time.Duration(z.PrepMinutes) * time.Minute
 }
 
 // ZoneCenter represents the center point of delivery zones
@@ -194,19 +371,44 @@ func (c *WebClient) DetermineZoneByCoords(lat, lng float64) (*DeliveryZone, erro
 		return nil, err
 	}
 
-	center, _ := c.GetZoneCenter()
+	var distance float64
+	for _, zone := range zones {
+		if zone.Geometry == nil {
+			// Calculate distance from center using Haversine formula, only ring zones need it
+			center, _ := c.GetZoneCenter()
+			distance = haversineDistance(center.Lat, center.Lng, lat, lng)
+			break
+		}
+	}
 
-	// Calculate distance from center using Haversine formula
-	distance := haversineDistance(center.Lat, center.Lng, lat, lng)
+	match := matchZone(zones, lat, lng, distance)
+	if match == nil {
+		return nil, fmt.Errorf("coordinates are outside delivery zones")
+	}
 
-	// Find matching zone (zone with smallest radius that contains the point)
-	for _, zone := range zones {
-		if distance >= zone.InnerRadius && distance < zone.Radius {
-			return &zone, nil
+	return match, nil
+}
+
+// matchZone finds the zone containing the point with the highest priority, the first one
+// listed on a tie. Ring zones are matched by distance, the km from the zone center.
+func matchZone(zones []DeliveryZone, lat, lng, distance float64) *DeliveryZone {
+	var match *DeliveryZone
+	for i := range zones {
+		zone := &zones[i]
+
+		var inside bool
+		if zone.Geometry != nil {
+			inside = zone.Geometry.Contains(lat, lng)
+		} else {
+			inside = distance >= zone.InnerRadius && distance < zone.Radius
+		}
+
+		if inside && (match == nil || zone.Priority > match.Priority) {
+			match = zone
 		}
 	}
 
-	return nil, fmt.Errorf("coordinates are outside delivery zones")
+	return match
 }
 
 // haversineDistance calculates distance between two points in km
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchZone(t *testing.T) {
+	square := func(from, to float64) *Geometry {
+		var g Geometry
+		data := fmt.Sprintf(`{"type": "Polygon", "coordinates": [[[%[1]g, %[1]g], [%[2]g, %[1]g], [%[2]g, %[2]g], [%[1]g, %[2]g]]]}`, from, to)
+		require.NoError(t, json.Unmarshal([]byte(data), &g))
+		return &g
+	}
+
+	zones := []DeliveryZone{
+		{Name: "city", Geometry: square(0, 10)},
+		{Name: "center", Geometry: square(4, 6), Priority: 10},
+		{Name: "center twin", Geometry: square(4, 6), Priority: 10},
+		{Name: "ring", Radius: 30, InnerRadius: 10},
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		distance float64
+		want     string
+	}{
+		{"only the city polygon", 2, 2, 50, "city"},
+		{"higher priority wins", 5, 5, 50, "center"},
+		{"polygon before ring on equal priority", 2, 2, 15, "city"},
+		{"ring by distance", 20, 20, 15, "ring"},
+		{"inside the inner radius", 20, 20, 5, ""},
+		{"at the outer radius", 20, 20, 30, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := matchZone(zones, tt.lat, tt.lng, tt.distance)
+			if tt.want == "" {
+				assert.Nil(t, match)
+				return
+			}
+			require.NotNil(t, match)
+			assert.Equal(t, tt.want, match.Name)
+		})
+	}
+}
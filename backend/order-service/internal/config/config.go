@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/tonysanin/brobar/order-service/internal/models"
 	"github.com/tonysanin/brobar/pkg/helpers"
 )
 
@@ -17,6 +19,11 @@ type Config struct {
 	DBName            string
 	DBSSLMode         string
 	AppTimezone       string
+	PaymentTTL        time.Duration
+	IdempotencyTTL    time.Duration
+	QuoteSecret       string
+	QuoteTTL          time.Duration
+	PrepLeadTimes     map[models.DeliveryType]time.Duration
 }
 
 func NewConfig() *Config {
@@ -31,9 +38,26 @@ func NewConfig() *Config {
 		DBName:            helpers.GetEnv("DB_NAME", ""),
 		DBSSLMode:         helpers.GetEnv("DB_SSLMODE", "disable"),
 		AppTimezone:       helpers.GetEnv("APP_TIMEZONE", "Europe/Kyiv"),
+		PaymentTTL:        parseDuration(helpers.GetEnv("ORDER_PAYMENT_TTL", "30m"), 30*time.Minute),
+		IdempotencyTTL:    parseDuration(helpers.GetEnv("ORDER_IDEMPOTENCY_TTL", "24h"), 24*time.Hour),
+		QuoteSecret:       helpers.GetEnv("ORDER_QUOTE_SECRET", ""),
+		QuoteTTL:          parseDuration(helpers.GetEnv("ORDER_QUOTE_TTL", "15m"), 15*time.Minute),
+		PrepLeadTimes: map[models.DeliveryType]time.Duration{
+			models.DeliveryTypeDelivery: parseDuration(helpers.GetEnv("ORDER_PREP_LEAD_DELIVERY", "60m"), 60*time.Minute),
+			models.DeliveryTypePickup:   parseDuration(helpers.GetEnv("ORDER_PREP_LEAD_PICKUP", "30m"), 30*time.Minute),
+			models.DeliveryTypeDine:     parseDuration(helpers.GetEnv("ORDER_PREP_LEAD_DINE", "30m"), 30*time.Minute),
+		},
 	}
 }
 
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
 func (c *Config) GetDatabaseURL() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName, c.DBSSLMode)
@@ -6,3 +6,7 @@ var (
 	OrderNotFound    = errors.New("order not found")
 	OrderInvalidData = errors.New("invalid order data")
 )
+
+var (
+	IdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	PromoCodeNotFound      = errors.New("promo code not found")
+	PromoCodeAlreadyExists = errors.New("promo code already exists")
+)
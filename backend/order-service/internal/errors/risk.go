@@ -0,0 +1,7 @@
+package errors
+
+import "errors"
+
+var (
+	PhoneFlagNotFound = errors.New("phone flag not found")
+)
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ContentType is the MIME type of the exported file
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// RowWriter writes a table row by row, Close flushes whatever is buffered.
+// Cells are strings, numbers (int, float64) or time.Time.
+type RowWriter interface {
+	WriteRow(cells []interface{}) error
+	Close() error
+}
+
+// NewWriter returns a row writer for the given format
+func NewWriter(format Format, w io.Writer, sheetName string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// formatCell renders a cell as text
+func formatCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(timeLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "github.com/google/uuid"
+
+// SalesGrouping is how sales are bucketed
+type SalesGrouping string
+
+const (
+	SalesByDay           SalesGrouping = "day"
+	SalesByHour          SalesGrouping = "hour"
+	SalesByHourOfDay     SalesGrouping = "hour_of_day"
+	SalesByDeliveryType  SalesGrouping = "delivery_type"
+	SalesByZone          SalesGrouping = "zone"
+	SalesByPaymentMethod SalesGrouping = "payment_method"
+)
+
+// SalesRow is revenue and order count for one bucket
+type SalesRow struct {
+	Key          string  `json:"key" db:"key"`
+	Orders       int     `json:"orders" db:"orders"`
+	Revenue      float64 `json:"revenue" db:"revenue"`
+	AverageCheck float64 `json:"average_check" db:"average_check"`
+}
+
+// TopSort is the measure top products and variations are ranked by
+type TopSort string
+
+const (
+	TopByQuantity TopSort = "quantity"
+	TopByRevenue  TopSort = "revenue"
+)
+
+// ProductSalesRow is how much of a product was sold, revenue is after discounts
+type ProductSalesRow struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Name      string    `json:"name" db:"name"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+	Revenue   float64   `json:"revenue" db:"revenue"`
+}
+
+// VariationSalesRow is how much of a variation was sold, revenue is its surcharges
+type VariationSalesRow struct {
+	VariationID uuid.UUID `json:"variation_id" db:"variation_id"`
+	Name        string    `json:"name" db:"name"`
+	GroupName   string    `json:"group_name" db:"group_name"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	Revenue     float64   `json:"revenue" db:"revenue"`
+}
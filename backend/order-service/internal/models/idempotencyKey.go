@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey remembers the outcome of a create request so client retries get the same answer
+type IdempotencyKey struct {
+	Key         string     `json:"key" db:"key"`
+	RequestHash string     `json:"request_hash" db:"request_hash"`
+	OrderID     *uuid.UUID `json:"order_id,omitempty" db:"order_id"`
+	Response    []byte     `json:"-" db:"response"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
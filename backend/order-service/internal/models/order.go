@@ -35,6 +35,15 @@ type Order struct {
 	Zone              *string      `json:"zone,omitempty" db:"zone"`
 	InvoiceID         *string      `json:"invoice_id,omitempty" db:"invoice_id"`
 	SyrveNotified     bool         `json:"syrve_notified" db:"syrve_notified"`
+	Discount          float64      `json:"discount" db:"discount"`
+	Scheduled         bool         `json:"scheduled" db:"scheduled"`
+	ReleaseAt         *time.Time   `json:"release_at,omitempty" db:"release_at"`
+	TrackingToken     *string      `json:"tracking_token,omitempty" db:"tracking_token"`
+	CourierStatus     *string      `json:"courier_status,omitempty" db:"courier_status"`
+	CourierUpdatedAt  *time.Time   `json:"courier_updated_at,omitempty" db:"courier_updated_at"`
+	LoyaltyPoints     int          `json:"loyalty_points" db:"loyalty_points"` // redeemed, one point is one hryvnia off
+	NoShow            bool         `json:"no_show" db:"no_show"`
+	RefundStatus      *string      `json:"refund_status,omitempty" db:"refund_status"` // set once the payment was refunded or handed to the staff
 	PaymentURL        string       `json:"payment_url,omitempty" db:"-"`
 
 	Items []OrderItem `json:"items" db:"-"`
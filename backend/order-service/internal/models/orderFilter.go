@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderFilter narrows down the admin order list, zero values mean "any".
+// Date ranges include From and exclude To.
+type OrderFilter struct {
+	StatusIDs       []Status
+	DeliveryTypeIDs []DeliveryType
+	PaymentMethod   string
+	Zone            string
+
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	TimeFrom    *time.Time
+	TimeTo      *time.Time
+
+	Phone string // substring
+	Name  string // substring, case-insensitive
+
+	TotalMin *float64
+	TotalMax *float64
+
+	Scheduled *bool // held until the kitchen start time
+
+	UserID *uuid.UUID // customer order history
+}
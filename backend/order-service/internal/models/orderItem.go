@@ -13,10 +13,14 @@ type OrderItem struct {
 	TotalPrice        float64   `json:"total_price" db:"total_price"`
 	Weight            float64   `json:"weight" db:"weight"`
 	TotalWeight       float64   `json:"total_weight" db:"total_weight"`
+	Discount          float64   `json:"discount" db:"discount"`
+	VariationsPrice   float64   `json:"variations_price" db:"variations_price"` // part of Price from variation surcharges
 
 	ProductVariationGroupID    *uuid.UUID `json:"product_variation_group_id" db:"product_variation_group_id"`
 	ProductVariationGroupName  *string    `json:"product_variation_group_name,omitempty" db:"product_variation_group_name" validate:"omitempty,min=1,max=255"`
 	ProductVariationID         *uuid.UUID `json:"product_variation_id" db:"product_variation_id"`
 	ProductVariationExternalID *string    `json:"product_variation_external_id,omitempty" db:"product_variation_external_id" validate:"max=100"`
 	ProductVariationName       *string    `json:"product_variation_name,omitempty" db:"product_variation_name" validate:"omitempty,min=1,max=255"`
+
+	Variations []OrderItemVariation `json:"variations,omitempty" db:"-"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+// OrderItemVariation is one variation selected for a cart line
+type OrderItemVariation struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	OrderItemID uuid.UUID `json:"order_item_id" db:"order_item_id"`
+	GroupID     uuid.UUID `json:"group_id" db:"group_id"`
+	GroupName   string    `json:"group_name" db:"group_name"`
+	VariationID uuid.UUID `json:"variation_id" db:"variation_id"`
+	ExternalID  string    `json:"external_id" db:"external_id"`
+	Name        string    `json:"name" db:"name"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	Price       float64   `json:"price" db:"price"` // surcharge per unit of the variation
+}
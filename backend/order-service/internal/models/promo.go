@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PromoDiscountType string
+
+const (
+	PromoDiscountPercent PromoDiscountType = "percent"
+	PromoDiscountFixed   PromoDiscountType = "fixed"
+)
+
+type PromoCode struct {
+	ID              uuid.UUID         `json:"id" db:"id"`
+	Code            string            `json:"code" db:"code"`
+	DiscountType    PromoDiscountType `json:"discount_type" db:"discount_type"`
+	DiscountValue   float64           `json:"discount_value" db:"discount_value"`
+	MinCart         float64           `json:"min_cart" db:"min_cart"`
+	StartsAt        *time.Time        `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt          *time.Time        `json:"ends_at,omitempty" db:"ends_at"`
+	MaxUses         *int              `json:"max_uses,omitempty" db:"max_uses"`
+	MaxUsesPerPhone *int              `json:"max_uses_per_phone,omitempty" db:"max_uses_per_phone"`
+	ProductIDs      pq.StringArray    `json:"product_ids" db:"product_ids"`
+	CategoryIDs     pq.StringArray    `json:"category_ids" db:"category_ids"`
+	Active          bool              `json:"active" db:"active"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// IsScoped reports whether the code only applies to specific products or categories
+func (p *PromoCode) IsScoped() bool {
+	return len(p.ProductIDs) > 0 || len(p.CategoryIDs) > 0
+}
+
+type PromoCodeUsage struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	PromoCodeID uuid.UUID `json:"promo_code_id" db:"promo_code_id"`
+	OrderID     uuid.UUID `json:"order_id" db:"order_id"`
+	Phone       string    `json:"phone" db:"phone"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type RiskAction string
+
+const (
+	RiskOnlineOnly RiskAction = "online_only" // cash is not accepted
+	RiskBlock      RiskAction = "block"       // no orders at all
+)
+
+// PhoneFlag marks a customer phone as risky
+type PhoneFlag struct {
+	PhoneKey  string     `json:"-" db:"phone_key"`
+	Phone     string     `json:"phone" db:"phone"`
+	Action    RiskAction `json:"action" db:"action"`
+	Reason    *string    `json:"reason,omitempty" db:"reason"`
+	FlaggedBy string     `json:"flagged_by" db:"flagged_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PhoneHistory counts the orders placed from a phone
+type PhoneHistory struct {
+	Orders    int `json:"orders" db:"orders"`
+	Completed int `json:"completed" db:"completed"`
+	NoShows   int `json:"no_shows" db:"no_shows"`
+}
+
+// PhoneRisk is what we know about a customer phone
+type PhoneRisk struct {
+	Flag    *PhoneFlag   `json:"flag"`
+	History PhoneHistory `json:"history"`
+}
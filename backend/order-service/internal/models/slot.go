@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SlotOrder is the share of a time slot taken by one order
+type SlotOrder struct {
+	Time  time.Time `db:"time"`
+	Items int       `db:"items"`
+}
+
+// TimeSlot is an order time the checkout can offer
+type TimeSlot struct {
+	Date      string `json:"date"`
+	Time      string `json:"time"`
+	Available bool   `json:"available"`
+}
+
+// DaySlots are the order times of the shift starting on Date, Reason explains
+// a holiday closure or special hours
+type DaySlots struct {
+	Date   string     `json:"date"`
+	Closed bool       `json:"closed"`
+	Reason string     `json:"reason,omitempty"`
+	Slots  []TimeSlot `json:"slots"`
+}
@@ -37,3 +37,28 @@ func (r Status) Value() (driver.Value, error) {
 		return nil, fmt.Errorf("invalid status: %s", r)
 	}
 }
+
+// statusTransitions lists the statuses an order may move to from each status.
+// Cancelled and completed are terminal. Skipping paid is for cash orders only, see CanTransitionTo.
+var statusTransitions = map[Status][]Status{
+	StatusPending:   {StatusPaid, StatusShipping, StatusCompleted, StatusCancelled},
+	StatusPaid:      {StatusShipping, StatusCompleted, StatusCancelled},
+	StatusShipping:  {StatusCompleted, StatusCancelled},
+	StatusCompleted: {},
+	StatusCancelled: {},
+}
+
+// CanTransitionTo reports whether an order paid with the payment method may move to next.
+// An order paid in cash is shipped or completed unpaid, any other waits for its payment.
+func (r Status) CanTransitionTo(next Status, paymentMethod string) bool {
+	if r == StatusPending && next != StatusPaid && next != StatusCancelled && paymentMethod != "cash" {
+		return false
+	}
+
+	for _, allowed := range statusTransitions[r] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
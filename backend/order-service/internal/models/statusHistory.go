@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderStatusHistory struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	OrderID    uuid.UUID `json:"order_id" db:"order_id"`
+	FromStatus *Status   `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   Status    `json:"to_status" db:"to_status"`
+	ChangedBy  string    `json:"changed_by" db:"changed_by"`
+	Reason     *string   `json:"reason,omitempty" db:"reason"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
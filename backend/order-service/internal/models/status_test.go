@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name          string
+		from, to      Status
+		paymentMethod string
+		want          bool
+	}{
+		{"pending to paid", StatusPending, StatusPaid, "online", true},
+		{"pending to cancelled", StatusPending, StatusCancelled, "online", true},
+		{"unpaid online order can't ship", StatusPending, StatusShipping, "online", false},
+		{"unpaid online order can't complete", StatusPending, StatusCompleted, "online", false},
+		{"cash order ships unpaid", StatusPending, StatusShipping, "cash", true},
+		{"cash order completes unpaid", StatusPending, StatusCompleted, "cash", true},
+		{"paid to shipping", StatusPaid, StatusShipping, "online", true},
+		{"paid to completed", StatusPaid, StatusCompleted, "online", true},
+		{"paid to cancelled", StatusPaid, StatusCancelled, "online", true},
+		{"paid back to pending", StatusPaid, StatusPending, "online", false},
+		{"shipping to completed", StatusShipping, StatusCompleted, "cash", true},
+		{"shipping back to paid", StatusShipping, StatusPaid, "online", false},
+		{"completed is terminal", StatusCompleted, StatusCancelled, "cash", false},
+		{"cancelled is terminal", StatusCancelled, StatusPaid, "online", false},
+		{"same status", StatusPaid, StatusPaid, "online", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to, tt.paymentMethod))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// CourierDispatched is set once the taxi for the delivery has been called
+const CourierDispatched = "dispatched"
+
+// OrderTracking is what the customer tracking page may see of an order
+type OrderTracking struct {
+	Number            string           `json:"number"` // as in notifications
+	Status            Status           `json:"status"`
+	DeliveryType      DeliveryType     `json:"delivery_type"`
+	Time              time.Time        `json:"time"`
+	ETA               *time.Time       `json:"eta,omitempty"`
+	Scheduled         bool             `json:"scheduled"`
+	AcceptedByKitchen bool             `json:"accepted_by_kitchen"`
+	Timeline          []TrackingEvent  `json:"timeline"`
+	Items             []TrackingItem   `json:"items"`
+	DeliveryCost      float64          `json:"delivery_cost"`
+	Discount          float64          `json:"discount"`
+	LoyaltyPoints     int              `json:"loyalty_points,omitempty"`
+	TotalPrice        float64          `json:"total_price"`
+	Courier           *TrackingCourier `json:"courier,omitempty"`
+}
+
+type TrackingEvent struct {
+	Status Status    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+type TrackingItem struct {
+	Name       string   `json:"name"`
+	Variations []string `json:"variations,omitempty"`
+	Quantity   int      `json:"quantity"`
+	TotalPrice float64  `json:"total_price"`
+}
+
+type TrackingCourier struct {
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// salesKeys maps a grouping to its SQL key expression, %[1]d is the timezone argument
+var salesKeys = map[models.SalesGrouping]string{
+	models.SalesByDay:           `to_char(created_at AT TIME ZONE $%[1]d, 'YYYY-MM-DD')`,
+	models.SalesByHour:          `to_char(created_at AT TIME ZONE $%[1]d, 'YYYY-MM-DD HH24:00')`,
+	models.SalesByHourOfDay:     `to_char(created_at AT TIME ZONE $%[1]d, 'HH24')`,
+	models.SalesByDeliveryType:  `delivery_type_id::text`,
+	models.SalesByZone:          `COALESCE(zone, '')`,
+	models.SalesByPaymentMethod: `COALESCE(payment_method, '')`,
+}
+
+type AnalyticsRepository struct {
+	db *sqlx.DB
+}
+
+func NewAnalyticsRepository(db *sqlx.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// GetSales returns revenue, order count and average check per bucket, time buckets
+// are taken in the given timezone
+func (r *AnalyticsRepository) GetSales(ctx context.Context, filter models.OrderFilter, grouping models.SalesGrouping, timezone string) ([]models.SalesRow, error) {
+	keyExpr, ok := salesKeys[grouping]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sales grouping: %s", grouping)
+	}
+
+	where, args := buildOrderFilter(filter)
+	args = append(args, timezone)
+
+	query := fmt.Sprintf(`
+		WITH filtered AS (SELECT * FROM orders %s)
+		SELECT
+			%s AS key,
+			COUNT(*) AS orders,
+			COALESCE(SUM(total_price), 0) AS revenue,
+			COALESCE(ROUND(AVG(total_price), 2), 0) AS average_check
+		FROM filtered
+		GROUP BY 1
+		ORDER BY 1
+	`, where, fmt.Sprintf(keyExpr, len(args)))
+
+	var rows []models.SalesRow
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get sales: %w", err)
+	}
+
+	if rows == nil {
+		return []models.SalesRow{}, nil
+	}
+
+	return rows, nil
+}
+
+// GetTopProducts returns the best selling products of the filtered orders
+func (r *AnalyticsRepository) GetTopProducts(ctx context.Context, filter models.OrderFilter, sort models.TopSort, limit int) ([]models.ProductSalesRow, error) {
+	where, args := buildOrderFilter(filter)
+	args = append(args, limit)
+
+	// MIN(name) picks the plain product name over the ones with variations appended
+	query := fmt.Sprintf(`
+		WITH filtered AS (SELECT id FROM orders %s)
+		SELECT
+			oi.product_id,
+			MIN(oi.name) AS name,
+			SUM(oi.quantity) AS quantity,
+			COALESCE(SUM(oi.total_price - COALESCE(oi.discount, 0)), 0) AS revenue
+		FROM order_items oi
+		JOIN filtered o ON o.id = oi.order_id
+		GROUP BY oi.product_id
+		ORDER BY %s DESC, name
+		LIMIT $%d
+	`, where, topSortColumn(sort), len(args))
+
+	var rows []models.ProductSalesRow
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get top products: %w", err)
+	}
+
+	if rows == nil {
+		return []models.ProductSalesRow{}, nil
+	}
+
+	return rows, nil
+}
+
+// GetTopVariations returns the most picked variations of the filtered orders. Items
+// from before multi-variation support only have the legacy single variation columns.
+func (r *AnalyticsRepository) GetTopVariations(ctx context.Context, filter models.OrderFilter, sort models.TopSort, limit int) ([]models.VariationSalesRow, error) {
+	where, args := buildOrderFilter(filter)
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		WITH filtered AS (SELECT id FROM orders %s),
+		picked AS (
+			SELECT v.variation_id, v.name, v.group_name,
+				v.quantity * oi.quantity AS quantity,
+				v.price * v.quantity * oi.quantity AS revenue
+			FROM order_item_variations v
+			JOIN order_items oi ON oi.id = v.order_item_id
+			JOIN filtered o ON o.id = oi.order_id
+			UNION ALL
+			SELECT oi.product_variation_id, COALESCE(oi.product_variation_name, ''), COALESCE(oi.product_variation_group_name, ''),
+				oi.quantity, 0
+			FROM order_items oi
+			JOIN filtered o ON o.id = oi.order_id
+			WHERE oi.product_variation_id IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM order_item_variations v WHERE v.order_item_id = oi.id)
+		)
+		SELECT
+			variation_id,
+			MIN(name) AS name,
+			MIN(group_name) AS group_name,
+			SUM(quantity) AS quantity,
+			COALESCE(SUM(revenue), 0) AS revenue
+		FROM picked
+		GROUP BY variation_id
+		ORDER BY %s DESC, name
+		LIMIT $%d
+	`, where, topSortColumn(sort), len(args))
+
+	var rows []models.VariationSalesRow
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get top variations: %w", err)
+	}
+
+	if rows == nil {
+		return []models.VariationSalesRow{}, nil
+	}
+
+	return rows, nil
+}
+
+func topSortColumn(sort models.TopSort) string {
+	if sort == models.TopByRevenue {
+		return "revenue"
+	}
+	return "quantity"
+}
@@ -1,7 +1,22 @@
 package repositories
 
-import "time"
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
 
 const (
 	defaultQueryTimeout = 5 * time.Second
+	exportQueryTimeout  = 2 * time.Minute
 )
+
+// dbExecutor is satisfied by both *sqlx.DB and *sqlx.Tx
+type dbExecutor interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
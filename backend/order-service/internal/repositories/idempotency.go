@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+type IdempotencyRepository struct {
+	db dbExecutor
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) WithTx(tx *sqlx.Tx) *IdempotencyRepository {
+	return &IdempotencyRepository{db: tx}
+}
+
+// ClaimKey stores the key if it is free, older than expiredBefore or an unfinished claim older
+// than leaseExpiredBefore. Returns false when another request already holds the key.
+func (r *IdempotencyRepository) ClaimKey(ctx context.Context, key, requestHash string, expiredBefore, leaseExpiredBefore time.Time) (bool, error) {
+	const deleteQuery = `
+		DELETE FROM idempotency_keys
+		WHERE key = $1 AND (created_at < $2 OR (response IS NULL AND created_at < $3))
+	`
+	const insertQuery = `
+		INSERT INTO idempotency_keys (key, request_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, deleteQuery, key, expiredBefore, leaseExpiredBefore); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return false, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to delete expired idempotency key: %v", err)
+		return false, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, insertQuery, key, requestHash, time.Now())
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return false, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to claim idempotency key: %v", err)
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get affected rows: %v", err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *IdempotencyRepository) GetKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	const query = `SELECT * FROM idempotency_keys WHERE key = $1`
+	var idempotencyKey models.IdempotencyKey
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.GetContext(ctx, &idempotencyKey, query, key)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.IdempotencyKeyNotFound
+		}
+		log.Printf("failed to get idempotency key: %v", err)
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &idempotencyKey, nil
+}
+
+// CompleteKey attaches the created order and the response returned to the client
+func (r *IdempotencyRepository) CompleteKey(ctx context.Context, key string, orderID uuid.UUID, response []byte) error {
+	const query = `UPDATE idempotency_keys SET order_id = $2, response = $3 WHERE key = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, key, orderID, response)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to complete idempotency key: %v", err)
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteKey(ctx context.Context, key string) error {
+	const query = `DELETE FROM idempotency_keys WHERE key = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to delete idempotency key: %v", err)
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context, before time.Time) (int64, error) {
+	const query = `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return 0, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to delete expired idempotency keys: %v", err)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -6,22 +6,28 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
 	"github.com/tonysanin/brobar/order-service/internal/models"
 )
 
 type OrderRepository struct {
-	db *sqlx.DB
+	db dbExecutor
 }
 
 func NewOrderRepository(db *sqlx.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
+func (r *OrderRepository) WithTx(tx *sqlx.Tx) *OrderRepository {
+	return &OrderRepository{db: tx}
+}
+
 func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]models.Order, error) {
 	const query = `SELECT * FROM orders`
 	var orders []models.Order
@@ -46,21 +52,24 @@ func (r *OrderRepository) GetAllOrders(ctx context.Context) ([]models.Order, err
 	return orders, nil
 }
 
-func (r *OrderRepository) GetOrdersWithPagination(ctx context.Context, limit, offset int, orderBy, orderDir string) ([]models.Order, int, error) {
+func (r *OrderRepository) GetOrdersWithPagination(ctx context.Context, filter models.OrderFilter, limit, offset int, orderBy, orderDir string) ([]models.Order, int, error) {
+	where, args := buildOrderFilter(filter)
+
 	queryOrders := fmt.Sprintf(`
 		SELECT * FROM orders
+		%s
 		ORDER BY %s %s
-		LIMIT $1 OFFSET $2
-	`, orderBy, orderDir)
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, orderDir, len(args)+1, len(args)+2)
 
-	const queryCount = `SELECT COUNT(*) FROM orders`
+	queryCount := fmt.Sprintf(`SELECT COUNT(*) FROM orders %s`, where)
 
 	var orders []models.Order
 
 	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
 	defer cancel()
 
-	err := r.db.SelectContext(ctx, &orders, queryOrders, limit, offset)
+	err := r.db.SelectContext(ctx, &orders, queryOrders, append(args, limit, offset)...)
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return nil, 0, fmt.Errorf("database query timed out")
@@ -69,7 +78,7 @@ func (r *OrderRepository) GetOrdersWithPagination(ctx context.Context, limit, of
 	}
 
 	var totalCount int
-	err = r.db.GetContext(ctx, &totalCount, queryCount)
+	err = r.db.GetContext(ctx, &totalCount, queryCount, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get orders total count: %w", err)
 	}
@@ -81,6 +90,176 @@ func (r *OrderRepository) GetOrdersWithPagination(ctx context.Context, limit, of
 	return orders, totalCount, nil
 }
 
+// ForEachOrderBatch walks the filtered orders oldest first, passing them to fn in batches
+func (r *OrderRepository) ForEachOrderBatch(ctx context.Context, filter models.OrderFilter, batchSize int, fn func([]models.Order) error) error {
+	where, args := buildOrderFilter(filter)
+	query := fmt.Sprintf(`SELECT * FROM orders %s ORDER BY created_at, id`, where)
+
+	ctx, cancel := context.WithTimeout(ctx, exportQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]models.Order, 0, batchSize)
+	for rows.Next() {
+		var order models.Order
+		if err := rows.StructScan(&order); err != nil {
+			return fmt.Errorf("failed to scan order: %w", err)
+		}
+		batch = append(batch, order)
+
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]models.Order, 0, batchSize)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate orders: %w", err)
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// buildOrderFilter turns the filter into a WHERE clause with positional arguments
+func buildOrderFilter(filter models.OrderFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(filter.StatusIDs) > 0 {
+		statuses := make([]string, len(filter.StatusIDs))
+		for i, status := range filter.StatusIDs {
+			statuses[i] = string(status)
+		}
+		add("status_id = ANY($%d)", pq.Array(statuses))
+	}
+	if len(filter.DeliveryTypeIDs) > 0 {
+		types := make([]string, len(filter.DeliveryTypeIDs))
+		for i, deliveryType := range filter.DeliveryTypeIDs {
+			types[i] = string(deliveryType)
+		}
+		add("delivery_type_id = ANY($%d)", pq.Array(types))
+	}
+	if filter.PaymentMethod != "" {
+		add("payment_method = $%d", filter.PaymentMethod)
+	}
+	if filter.Zone != "" {
+		add("zone = $%d", filter.Zone)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.TimeFrom != nil {
+		add("time >= $%d", *filter.TimeFrom)
+	}
+	if filter.TimeTo != nil {
+		add("time < $%d", *filter.TimeTo)
+	}
+	if filter.Phone != "" {
+		// A local prefix may also be a fragment from the middle of the number, so both are matched
+		if digits, stored := phoneSearchDigits(filter.Phone); digits != "" {
+			args = append(args, "%"+digits+"%", "%"+stored+"%")
+			conditions = append(conditions, fmt.Sprintf(
+				"(regexp_replace(phone, '[^0-9]', '', 'g') LIKE $%d OR regexp_replace(phone, '[^0-9]', '', 'g') LIKE $%d)",
+				len(args)-1, len(args)))
+		} else {
+			add("phone LIKE $%d", "%"+escapeLike(filter.Phone)+"%")
+		}
+	}
+	if filter.Name != "" {
+		add("name ILIKE $%d", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.TotalMin != nil {
+		add("total_price >= $%d", *filter.TotalMin)
+	}
+	if filter.TotalMax != nil {
+		add("total_price <= $%d", *filter.TotalMax)
+	}
+	if filter.Scheduled != nil {
+		add("scheduled = $%d", *filter.Scheduled)
+	}
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// phoneSearchDigits returns the digits of a typed phone or a part of it, and the same digits
+// as they appear in the stored E.164 number: the local "0…" and "80…" prefixes become "380…",
+// the way validator.NormalizePhone completes numbers
+func phoneSearchDigits(value string) (digits, stored string) {
+	digits = strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if strings.HasPrefix(strings.TrimSpace(value), "+") || strings.HasPrefix(digits, "380") {
+		return digits, digits
+	}
+	switch {
+	case strings.HasPrefix(digits, "80"):
+		return digits, "3" + digits
+	case strings.HasPrefix(digits, "0"):
+		return digits, "38" + digits
+	}
+	return digits, digits
+}
+
+// escapeLike escapes the LIKE wildcards so the value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// GetUnpaidOnlineOrders returns pending online orders created before the given time
+func (r *OrderRepository) GetUnpaidOnlineOrders(ctx context.Context, createdBefore time.Time) ([]models.Order, error) {
+	const query = `
+		SELECT * FROM orders
+		WHERE status_id = $1 AND payment_method = 'online' AND created_at < $2
+		ORDER BY created_at
+	`
+	var orders []models.Order
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &orders, query, models.StatusPending, createdBefore)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get unpaid orders: %v", err)
+		return nil, fmt.Errorf("failed to get unpaid orders: %w", err)
+	}
+
+	return orders, nil
+}
+
 func (r *OrderRepository) GetOrderById(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	const query = `
 		SELECT
@@ -111,6 +290,15 @@ func (r *OrderRepository) GetOrderById(ctx context.Context, id uuid.UUID) (*mode
 			o.zone as "order.zone",
 			o.invoice_id as "order.invoice_id",
 			o.syrve_notified as "order.syrve_notified",
+			o.discount as "order.discount",
+			o.scheduled as "order.scheduled",
+			o.release_at as "order.release_at",
+			o.tracking_token as "order.tracking_token",
+			o.courier_status as "order.courier_status",
+			o.courier_updated_at as "order.courier_updated_at",
+			o.loyalty_points as "order.loyalty_points",
+			o.no_show as "order.no_show",
+			o.refund_status as "order.refund_status",
 
 			oi.id as "items.id",
 			oi.order_id as "items.order_id",
@@ -122,6 +310,8 @@ func (r *OrderRepository) GetOrderById(ctx context.Context, id uuid.UUID) (*mode
 			oi.total_price as "items.total_price",
 			oi.weight as "items.weight",
 			oi.total_weight as "items.total_weight",
+			oi.discount as "items.discount",
+			oi.variations_price as "items.variations_price",
 
 			oi.product_variation_group_id as "items.product_variation_group_id",
 			oi.product_variation_group_name as "items.product_variation_group_name",
@@ -171,6 +361,15 @@ func (r *OrderRepository) GetOrderByInvoiceID(ctx context.Context, invoiceID str
 			o.zone as "order.zone",
 			o.invoice_id as "order.invoice_id",
 			o.syrve_notified as "order.syrve_notified",
+			o.discount as "order.discount",
+			o.scheduled as "order.scheduled",
+			o.release_at as "order.release_at",
+			o.tracking_token as "order.tracking_token",
+			o.courier_status as "order.courier_status",
+			o.courier_updated_at as "order.courier_updated_at",
+			o.loyalty_points as "order.loyalty_points",
+			o.no_show as "order.no_show",
+			o.refund_status as "order.refund_status",
 
 			oi.id as "items.id",
 			oi.order_id as "items.order_id",
@@ -182,6 +381,8 @@ func (r *OrderRepository) GetOrderByInvoiceID(ctx context.Context, invoiceID str
 			oi.total_price as "items.total_price",
 			oi.weight as "items.weight",
 			oi.total_weight as "items.total_weight",
+			oi.discount as "items.discount",
+			oi.variations_price as "items.variations_price",
 
 			oi.product_variation_group_id as "items.product_variation_group_id",
 			oi.product_variation_group_name as "items.product_variation_group_name",
@@ -231,6 +432,7 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			deliveryCost          *float64
 			deliveryDoor          *bool
 			deliveryDoorPrice     *float64
+			discount              *float64
 
 			oiID                *uuid.UUID
 			oiOrderID           *uuid.UUID
@@ -242,6 +444,8 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			oiTotalPrice        *float64
 			oiWeight            *float64
 			oiTotalWeight       *float64
+			oiDiscount          *float64
+			oiVariationsPrice   *float64
 
 			variationGroupID    *uuid.UUID
 			variationGroupName  *string
@@ -278,6 +482,15 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			&o.Zone,
 			&o.InvoiceID,
 			&o.SyrveNotified,
+			&discount,
+			&o.Scheduled,
+			&o.ReleaseAt,
+			&o.TrackingToken,
+			&o.CourierStatus,
+			&o.CourierUpdatedAt,
+			&o.LoyaltyPoints,
+			&o.NoShow,
+			&o.RefundStatus,
 
 			&oiID,
 			&oiOrderID,
@@ -289,6 +502,8 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			&oiTotalPrice,
 			&oiWeight,
 			&oiTotalWeight,
+			&oiDiscount,
+			&oiVariationsPrice,
 
 			&variationGroupID,
 			&variationGroupName,
@@ -345,6 +560,9 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			if paymentMethod != nil {
 				order.PaymentMethod = *paymentMethod
 			}
+			if discount != nil {
+				order.Discount = *discount
+			}
 			order.Zone = zone
 			first = false
 		}
@@ -380,6 +598,12 @@ func (r *OrderRepository) scanOrder(err error, rows *sqlx.Rows, ctx context.Cont
 			if oiTotalWeight != nil {
 				oi.TotalWeight = *oiTotalWeight
 			}
+			if oiDiscount != nil {
+				oi.Discount = *oiDiscount
+			}
+			if oiVariationsPrice != nil {
+				oi.VariationsPrice = *oiVariationsPrice
+			}
 
 			oi.ProductVariationGroupID = variationGroupID
 			oi.ProductVariationGroupName = variationGroupName
@@ -409,12 +633,14 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order *models.Order)
 			id, user_id, status_id, total_price, created_at, updated_at,
 			address, entrance, floor, flat, address_wishes, name, phone,
 			time, email, wishes, promo, coords, cutlery, delivery_cost,
-			delivery_door, delivery_door_price, delivery_type_id, payment_method, zone, invoice_id, syrve_notified
+			delivery_door, delivery_door_price, delivery_type_id, payment_method, zone, invoice_id, syrve_notified,
+			discount, scheduled, release_at, tracking_token, loyalty_points
 		) VALUES (
 			:id, :user_id, :status_id, :total_price, :created_at, :updated_at,
 			:address, :entrance, :floor, :flat, :address_wishes, :name, :phone,
 			:time, :email, :wishes, :promo, :coords, :cutlery, :delivery_cost,
-			:delivery_door, :delivery_door_price, :delivery_type_id, :payment_method, :zone, :invoice_id, :syrve_notified
+			:delivery_door, :delivery_door_price, :delivery_type_id, :payment_method, :zone, :invoice_id, :syrve_notified,
+			:discount, :scheduled, :release_at, :tracking_token, :loyalty_points
 		)
 	`
 
@@ -469,7 +695,8 @@ func (r *OrderRepository) UpdateOrder(ctx context.Context, order *models.Order)
 			payment_method = :payment_method,
 			zone = :zone,
 			invoice_id = :invoice_id,
-			syrve_notified = :syrve_notified
+			syrve_notified = :syrve_notified,
+			discount = :discount
 		WHERE id = :id
 	`
 
@@ -548,3 +775,270 @@ func (r *OrderRepository) SetSyrveNotified(ctx context.Context, id uuid.UUID) (b
 
 	return rowsAffected > 0, nil
 }
+
+// GetOrderIDByTrackingToken finds the order a customer tracking link points to
+func (r *OrderRepository) GetOrderIDByTrackingToken(ctx context.Context, token string) (uuid.UUID, error) {
+	const query = `SELECT id FROM orders WHERE tracking_token = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	var id uuid.UUID
+	if err := r.db.GetContext(ctx, &id, query, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, customerrors.OrderNotFound
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return uuid.Nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get order by tracking token: %v", err)
+		return uuid.Nil, fmt.Errorf("failed to get order by tracking token: %w", err)
+	}
+
+	return id, nil
+}
+
+// SetCourierStatus records the delivery state reported by the taxi integration
+func (r *OrderRepository) SetCourierStatus(ctx context.Context, id uuid.UUID, status string, at time.Time) error {
+	const query = `UPDATE orders SET courier_status = $1, courier_updated_at = $2, updated_at = $2 WHERE id = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, status, at, id)
+	if err != nil {
+		log.Printf("failed to set courier status: %v", err)
+		return fmt.Errorf("failed to set courier status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.OrderNotFound
+	}
+
+	return nil
+}
+
+// SetNoShow marks the order as one the customer did not take
+func (r *OrderRepository) SetNoShow(ctx context.Context, id uuid.UUID) error {
+	const query = `UPDATE orders SET no_show = TRUE, updated_at = $1 WHERE id = $2`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		log.Printf("failed to set no-show: %v", err)
+		return fmt.Errorf("failed to set no-show: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.OrderNotFound
+	}
+
+	return nil
+}
+
+// LockRefundStatus locks the order row until the end of the transaction and returns its refund status
+func (r *OrderRepository) LockRefundStatus(ctx context.Context, id uuid.UUID) (*string, error) {
+	const query = `SELECT refund_status FROM orders WHERE id = $1 FOR UPDATE`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	var status *string
+	if err := r.db.GetContext(ctx, &status, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.OrderNotFound
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to lock order refund status: %v", err)
+		return nil, fmt.Errorf("failed to lock order refund status: %w", err)
+	}
+
+	return status, nil
+}
+
+// SetRefundStatus records what happened to the payment of a cancelled order
+func (r *OrderRepository) SetRefundStatus(ctx context.Context, id uuid.UUID, status string) error {
+	const query = `UPDATE orders SET refund_status = $1, updated_at = $2 WHERE id = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		log.Printf("failed to set refund status: %v", err)
+		return fmt.Errorf("failed to set refund status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.OrderNotFound
+	}
+
+	return nil
+}
+
+// GetPhoneHistory counts the orders whose phone has the given key, leaving out the excluded order
+func (r *OrderRepository) GetPhoneHistory(ctx context.Context, phoneKey string, excludeID uuid.UUID) (*models.PhoneHistory, error) {
+	const query = `
+		SELECT
+			COUNT(*) AS orders,
+			COUNT(*) FILTER (WHERE status_id = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE no_show) AS no_shows
+		FROM orders
+		WHERE RIGHT(regexp_replace(phone, '[^0-9]', '', 'g'), 9) = $1 AND id <> $2`
+	var history models.PhoneHistory
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.GetContext(ctx, &history, query, phoneKey, excludeID); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get phone history: %v", err)
+		return nil, fmt.Errorf("failed to get phone history: %w", err)
+	}
+
+	return &history, nil
+}
+
+// UpdateOrderStatus moves the order from one status to another.
+// It returns false when the order is no longer in the expected status.
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, id uuid.UUID, from, to models.Status) (bool, error) {
+	const query = `UPDATE orders SET status_id = $1, updated_at = $2 WHERE id = $3 AND status_id = $4`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, to, time.Now(), id, from)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return false, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to update order status: %v", err)
+		return false, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetDueScheduledOrders returns held orders whose release time has come. Unpaid online
+// orders stay held until the payment arrives.
+func (r *OrderRepository) GetDueScheduledOrders(ctx context.Context, now time.Time) ([]models.Order, error) {
+	const query = `
+		SELECT * FROM orders
+		WHERE scheduled = TRUE AND release_at <= $1
+			AND status_id <> $2
+			AND NOT (payment_method = 'online' AND status_id = $3)
+		ORDER BY release_at
+	`
+	var orders []models.Order
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &orders, query, now, models.StatusCancelled, models.StatusPending)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get scheduled orders: %v", err)
+		return nil, fmt.Errorf("failed to get scheduled orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// ReleaseScheduled takes the hold off the order and records when it was released.
+// It returns false when the order is not held (already released by someone else).
+func (r *OrderRepository) ReleaseScheduled(ctx context.Context, id uuid.UUID, releasedAt time.Time) (bool, error) {
+	const query = `UPDATE orders SET scheduled = FALSE, release_at = $1, updated_at = $1 WHERE id = $2 AND scheduled = TRUE`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, releasedAt, id)
+	if err != nil {
+		log.Printf("failed to release scheduled order: %v", err)
+		return false, fmt.Errorf("failed to release scheduled order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetSlotOrders returns the non-cancelled orders of a delivery type requested for
+// [from, to), with their item counts
+func (r *OrderRepository) GetSlotOrders(ctx context.Context, deliveryType models.DeliveryType, from, to time.Time) ([]models.SlotOrder, error) {
+	const query = `
+		SELECT o.time, COALESCE(SUM(oi.quantity), 0) AS items
+		FROM orders o
+		LEFT JOIN order_items oi ON oi.order_id = o.id
+		WHERE o.delivery_type_id = $1 AND o.time >= $2 AND o.time < $3 AND o.status_id <> $4
+		GROUP BY o.id, o.time
+	`
+	var orders []models.SlotOrder
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &orders, query, deliveryType, from, to, models.StatusCancelled)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get slot orders: %v", err)
+		return nil, fmt.Errorf("failed to get slot orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// LockSlot serializes order creation for a time slot until the transaction ends,
+// so it must run within WithTx
+func (r *OrderRepository) LockSlot(ctx context.Context, key string) error {
+	const query = `SELECT pg_advisory_xact_lock(hashtext($1))`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, query, key); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to lock time slot: %w", err)
+	}
+
+	return nil
+}
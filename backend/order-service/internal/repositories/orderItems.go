@@ -9,25 +9,54 @@ import (
 )
 
 type OrderItemRepository struct {
-	db *sqlx.DB
+	db dbExecutor
 }
 
 func NewOrderItemRepository(db *sqlx.DB) *OrderItemRepository {
 	return &OrderItemRepository{db: db}
 }
 
+func (r *OrderItemRepository) WithTx(tx *sqlx.Tx) *OrderItemRepository {
+	return &OrderItemRepository{db: tx}
+}
+
 func (r *OrderItemRepository) CreateOrderItem(ctx context.Context, item *models.OrderItem) error {
 	query := `
 		INSERT INTO order_items (
-			id, order_id, product_id, external_product_id, name, price, quantity, total_price, weight, total_weight,
+			id, order_id, product_id, external_product_id, name, price, quantity, total_price, weight, total_weight, discount, variations_price,
 			product_variation_group_id, product_variation_group_name, product_variation_id, product_variation_external_id, product_variation_name
 		) VALUES (
-			:id, :order_id, :product_id, :external_product_id, :name, :price, :quantity, :total_price, :weight, :total_weight,
+			:id, :order_id, :product_id, :external_product_id, :name, :price, :quantity, :total_price, :weight, :total_weight, :discount, :variations_price,
 			:product_variation_group_id, :product_variation_group_name, :product_variation_id, :product_variation_external_id, :product_variation_name
 		)
 	`
-	_, err := r.db.NamedExecContext(ctx, query, item)
-	return err
+	if _, err := r.db.NamedExecContext(ctx, query, item); err != nil {
+		return err
+	}
+
+	return r.createOrderItemVariations(ctx, item)
+}
+
+func (r *OrderItemRepository) createOrderItemVariations(ctx context.Context, item *models.OrderItem) error {
+	query := `
+		INSERT INTO order_item_variations (
+			id, order_item_id, group_id, group_name, variation_id, external_id, name, quantity, price
+		) VALUES (
+			:id, :order_item_id, :group_id, :group_name, :variation_id, :external_id, :name, :quantity, :price
+		)
+	`
+	for i := range item.Variations {
+		variation := &item.Variations[i]
+		if variation.ID == uuid.Nil {
+			variation.ID = uuid.New()
+		}
+		variation.OrderItemID = item.ID
+
+		if _, err := r.db.NamedExecContext(ctx, query, variation); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *OrderItemRepository) CreateOrderItems(ctx context.Context, items []models.OrderItem) error {
@@ -63,6 +92,30 @@ func (r *OrderItemRepository) GetOrderItemsByOrderIDs(ctx context.Context, order
 	return items, nil
 }
 
+func (r *OrderItemRepository) GetVariationsByOrderItemIDs(ctx context.Context, itemIDs []uuid.UUID) ([]models.OrderItemVariation, error) {
+	if len(itemIDs) == 0 {
+		return []models.OrderItemVariation{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM order_item_variations WHERE order_item_id IN (?)`, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	var variations []models.OrderItemVariation
+	err = r.db.SelectContext(ctx, &variations, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if variations == nil {
+		return []models.OrderItemVariation{}, nil
+	}
+
+	return variations, nil
+}
+
 func (r *OrderItemRepository) DeleteOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) error {
 	query := `DELETE FROM order_items WHERE order_id = $1`
 	_, err := r.db.ExecContext(ctx, query, orderID)
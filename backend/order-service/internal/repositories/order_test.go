@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoneSearchDigits(t *testing.T) {
+	tests := []struct {
+		in, digits, stored string
+	}{
+		{"067", "067", "38067"},
+		{"067 123-45", "06712345", "3806712345"},
+		{"8067", "8067", "38067"},
+		{"+38067", "38067", "38067"},
+		{"380671234567", "380671234567", "380671234567"},
+		{"+48 512", "48512", "48512"},
+		{"1234", "1234", "1234"},
+		{"abc", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			digits, stored := phoneSearchDigits(tt.in)
+			assert.Equal(t, tt.digits, digits)
+			assert.Equal(t, tt.stored, stored)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+type OutboxRepository struct {
+	db dbExecutor
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) WithTx(tx *sqlx.Tx) *OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+func (r *OutboxRepository) CreateEvent(ctx context.Context, event *models.OutboxEvent) error {
+	const query = `
+		INSERT INTO outbox_events (id, queue, payload, attempts, created_at, next_attempt_at)
+		VALUES (:id, :queue, :payload, :attempts, :created_at, :next_attempt_at)
+	`
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.CreatedAt
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.NamedExecContext(ctx, query, event); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to create outbox event: %v", err)
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimPendingEvents takes unsent events that are due, oldest first, and postpones them to
+// claimedUntil, so other relays leave them alone while they are delivered. An event whose
+// delivery never gets marked is picked up again after claimedUntil.
+func (r *OutboxRepository) ClaimPendingEvents(ctx context.Context, now, claimedUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	const query = `
+		UPDATE outbox_events SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE sent_at IS NULL AND next_attempt_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	var events []models.OutboxEvent
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.SelectContext(ctx, &events, query, now, claimedUntil, limit); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to claim pending outbox events: %v", err)
+		return nil, fmt.Errorf("failed to claim pending outbox events: %w", err)
+	}
+
+	// RETURNING keeps no order
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	const query = `UPDATE outbox_events SET sent_at = $2, attempts = attempts + 1, last_error = NULL WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, query, id, sentAt); err != nil {
+		return fmt.Errorf("failed to mark outbox event sent: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed publish and schedules the next attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	const query = `UPDATE outbox_events SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, query, id, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSentBefore removes events published before the given time
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	const query = `DELETE FROM outbox_events WHERE sent_at IS NOT NULL AND sent_at < $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
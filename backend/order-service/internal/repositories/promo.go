@@ -0,0 +1,292 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+type PromoRepository struct {
+	db dbExecutor
+}
+
+func NewPromoRepository(db *sqlx.DB) *PromoRepository {
+	return &PromoRepository{db: db}
+}
+
+func (r *PromoRepository) WithTx(tx *sqlx.Tx) *PromoRepository {
+	return &PromoRepository{db: tx}
+}
+
+func (r *PromoRepository) GetAllPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	const query = `SELECT * FROM promo_codes ORDER BY created_at DESC`
+	var promos []models.PromoCode
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &promos, query)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get promo codes: %v", err)
+		return nil, fmt.Errorf("failed to get promo codes: %w", err)
+	}
+
+	if promos == nil {
+		return []models.PromoCode{}, nil
+	}
+
+	return promos, nil
+}
+
+func (r *PromoRepository) GetPromoCodeById(ctx context.Context, id uuid.UUID) (*models.PromoCode, error) {
+	const query = `SELECT * FROM promo_codes WHERE id = $1`
+	return r.getPromoCode(ctx, query, id)
+}
+
+// GetPromoCodeByCode looks a code up case-insensitively, as customers type it by hand.
+// Codes are stored uppercase, so the lookup uses the unique index on code.
+func (r *PromoRepository) GetPromoCodeByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	const query = `SELECT * FROM promo_codes WHERE code = $1`
+	return r.getPromoCode(ctx, query, strings.ToUpper(code))
+}
+
+func (r *PromoRepository) getPromoCode(ctx context.Context, query string, arg interface{}) (*models.PromoCode, error) {
+	var promo models.PromoCode
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.GetContext(ctx, &promo, query, arg)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.PromoCodeNotFound
+		}
+		log.Printf("failed to get promo code: %v", err)
+		return nil, fmt.Errorf("failed to get promo code: %w", err)
+	}
+
+	return &promo, nil
+}
+
+func (r *PromoRepository) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	const query = `
+		INSERT INTO promo_codes (
+			id, code, discount_type, discount_value, min_cart, starts_at, ends_at,
+			max_uses, max_uses_per_phone, product_ids, category_ids, active, created_at, updated_at
+		) VALUES (
+			:id, :code, :discount_type, :discount_value, :min_cart, :starts_at, :ends_at,
+			:max_uses, :max_uses_per_phone, :product_ids, :category_ids, :active, :created_at, :updated_at
+		)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, query, promo)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return customerrors.PromoCodeAlreadyExists
+		}
+
+		log.Printf("failed to create promo code: %v", err)
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PromoRepository) UpdatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	const query = `
+		UPDATE promo_codes SET
+			code = :code,
+			discount_type = :discount_type,
+			discount_value = :discount_value,
+			min_cart = :min_cart,
+			starts_at = :starts_at,
+			ends_at = :ends_at,
+			max_uses = :max_uses,
+			max_uses_per_phone = :max_uses_per_phone,
+			product_ids = :product_ids,
+			category_ids = :category_ids,
+			active = :active,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.NamedExecContext(ctx, query, promo)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return customerrors.PromoCodeAlreadyExists
+		}
+
+		log.Printf("failed to update promo code: %v", err)
+		return fmt.Errorf("failed to update promo code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get affected rows: %v", err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.PromoCodeNotFound
+	}
+
+	return nil
+}
+
+func (r *PromoRepository) DeletePromoCode(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM promo_codes WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to delete promo code: %v", err)
+		return fmt.Errorf("failed to delete promo code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get affected rows: %v", err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.PromoCodeNotFound
+	}
+
+	return nil
+}
+
+// LockPromoCode holds the code row until the transaction ends, so usages of the code are
+// counted and written by one order at a time. It must run within WithTx.
+func (r *PromoRepository) LockPromoCode(ctx context.Context, id uuid.UUID) error {
+	const query = `SELECT id FROM promo_codes WHERE id = $1 FOR UPDATE`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	var locked uuid.UUID
+	if err := r.db.GetContext(ctx, &locked, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerrors.PromoCodeNotFound
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to lock promo code: %w", err)
+	}
+
+	return nil
+}
+
+// CountUsages returns the total number of usages of a code and the number made by the given phone
+func (r *PromoRepository) CountUsages(ctx context.Context, promoCodeID uuid.UUID, phone string) (int, int, error) {
+	const query = `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE phone = $2) AS by_phone
+		FROM promo_code_usages
+		WHERE promo_code_id = $1
+	`
+
+	var counts struct {
+		Total   int `db:"total"`
+		ByPhone int `db:"by_phone"`
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.GetContext(ctx, &counts, query, promoCodeID, phone)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, 0, fmt.Errorf("database query timed out")
+		}
+		return 0, 0, fmt.Errorf("failed to count promo code usages: %w", err)
+	}
+
+	return counts.Total, counts.ByPhone, nil
+}
+
+func (r *PromoRepository) CreateUsage(ctx context.Context, usage *models.PromoCodeUsage) error {
+	const query = `
+		INSERT INTO promo_code_usages (id, promo_code_id, order_id, phone, created_at)
+		VALUES (:id, :promo_code_id, :order_id, :phone, :created_at)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if usage.ID == uuid.Nil {
+		usage.ID = uuid.New()
+	}
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.NamedExecContext(ctx, query, usage)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to create promo code usage: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUsagesByOrderID frees the usages of a cancelled order for other orders
+func (r *PromoRepository) DeleteUsagesByOrderID(ctx context.Context, orderID uuid.UUID) error {
+	const query = `DELETE FROM promo_code_usages WHERE order_id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, query, orderID); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to delete promo code usages: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+type PhoneFlagRepository struct {
+	db *sqlx.DB
+}
+
+func NewPhoneFlagRepository(db *sqlx.DB) *PhoneFlagRepository {
+	return &PhoneFlagRepository{db: db}
+}
+
+func (r *PhoneFlagRepository) GetAllFlags(ctx context.Context) ([]models.PhoneFlag, error) {
+	const query = `SELECT * FROM phone_flags ORDER BY updated_at DESC`
+	var flags []models.PhoneFlag
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &flags, query)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get phone flags: %v", err)
+		return nil, fmt.Errorf("failed to get phone flags: %w", err)
+	}
+
+	if flags == nil {
+		return []models.PhoneFlag{}, nil
+	}
+
+	return flags, nil
+}
+
+func (r *PhoneFlagRepository) GetFlag(ctx context.Context, phoneKey string) (*models.PhoneFlag, error) {
+	const query = `SELECT * FROM phone_flags WHERE phone_key = $1`
+	var flag models.PhoneFlag
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.GetContext(ctx, &flag, query, phoneKey)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.PhoneFlagNotFound
+		}
+		log.Printf("failed to get phone flag: %v", err)
+		return nil, fmt.Errorf("failed to get phone flag: %w", err)
+	}
+
+	return &flag, nil
+}
+
+// UpsertFlag flags the phone or replaces its existing flag
+func (r *PhoneFlagRepository) UpsertFlag(ctx context.Context, flag *models.PhoneFlag) error {
+	const query = `
+		INSERT INTO phone_flags (phone_key, phone, action, reason, flagged_by, created_at, updated_at)
+		VALUES (:phone_key, :phone, :action, :reason, :flagged_by, :created_at, :updated_at)
+		ON CONFLICT (phone_key) DO UPDATE SET
+			phone = EXCLUDED.phone,
+			action = EXCLUDED.action,
+			reason = EXCLUDED.reason,
+			flagged_by = EXCLUDED.flagged_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at`
+
+	now := time.Now()
+	flag.CreatedAt = now
+	flag.UpdatedAt = now
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.NamedQueryContext(ctx, query, flag)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to save phone flag: %v", err)
+		return fmt.Errorf("failed to save phone flag: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&flag.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan phone flag: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *PhoneFlagRepository) DeleteFlag(ctx context.Context, phoneKey string) error {
+	const query = `DELETE FROM phone_flags WHERE phone_key = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, phoneKey)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to delete phone flag: %v", err)
+		return fmt.Errorf("failed to delete phone flag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get affected rows: %v", err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return customerrors.PhoneFlagNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+type StatusHistoryRepository struct {
+	db dbExecutor
+}
+
+func NewStatusHistoryRepository(db *sqlx.DB) *StatusHistoryRepository {
+	return &StatusHistoryRepository{db: db}
+}
+
+func (r *StatusHistoryRepository) WithTx(tx *sqlx.Tx) *StatusHistoryRepository {
+	return &StatusHistoryRepository{db: tx}
+}
+
+func (r *StatusHistoryRepository) CreateStatusHistory(ctx context.Context, entry *models.OrderStatusHistory) error {
+	const query = `
+		INSERT INTO order_status_history (id, order_id, from_status, to_status, changed_by, reason, created_at)
+		VALUES (:id, :order_id, :from_status, :to_status, :changed_by, :reason, :created_at)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.NamedExecContext(ctx, query, entry)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to create status history: %v", err)
+		return fmt.Errorf("failed to create status history: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StatusHistoryRepository) GetStatusHistoryByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.OrderStatusHistory, error) {
+	const query = `SELECT * FROM order_status_history WHERE order_id = $1 ORDER BY created_at`
+	var history []models.OrderStatusHistory
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &history, query, orderID)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get status history: %v", err)
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+
+	if history == nil {
+		return []models.OrderStatusHistory{}, nil
+	}
+
+	return history, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+// revenueStatuses are counted when no status filter is given, cancelled orders never brought money
+var revenueStatuses = []models.Status{
+	models.StatusPending,
+	models.StatusPaid,
+	models.StatusShipping,
+	models.StatusCompleted,
+}
+
+type AnalyticsService struct {
+	repository *repositories.AnalyticsRepository
+	location   *time.Location
+}
+
+func NewAnalyticsService(repository *repositories.AnalyticsRepository, location *time.Location) *AnalyticsService {
+	return &AnalyticsService{repository: repository, location: location}
+}
+
+// GetSales returns revenue, order count and average check grouped by time (in the
+// service timezone), delivery type, zone or payment method
+func (s *AnalyticsService) GetSales(ctx context.Context, filter models.OrderFilter, grouping models.SalesGrouping) ([]models.SalesRow, error) {
+	return s.repository.GetSales(ctx, withRevenueStatuses(filter), grouping, s.location.String())
+}
+
+func (s *AnalyticsService) GetTopProducts(ctx context.Context, filter models.OrderFilter, sort models.TopSort, limit int) ([]models.ProductSalesRow, error) {
+	return s.repository.GetTopProducts(ctx, withRevenueStatuses(filter), sort, limit)
+}
+
+func (s *AnalyticsService) GetTopVariations(ctx context.Context, filter models.OrderFilter, sort models.TopSort, limit int) ([]models.VariationSalesRow, error) {
+	return s.repository.GetTopVariations(ctx, withRevenueStatuses(filter), sort, limit)
+}
+
+func withRevenueStatuses(filter models.OrderFilter) models.OrderFilter {
+	if len(filter.StatusIDs) == 0 {
+		filter.StatusIDs = revenueStatuses
+	}
+	return filter
+}
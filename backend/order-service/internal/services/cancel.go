@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/helpers"
+	"github.com/tonysanin/brobar/pkg/rabbitmq"
+)
+
+var (
+	ErrRefundFailed             = errors.New("не вдалося повернути кошти за замовлення")
+	ErrInvoiceCancelFailed      = errors.New("не вдалося анулювати рахунок на оплату")
+	ErrStatusCancelledViaCancel = errors.New("замовлення скасовується лише окремою дією скасування")
+)
+
+// OrderCancelEvent asks syrve-service to cancel an order the kitchen already has
+type OrderCancelEvent struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// cancellation is what was done with the money and the kitchen ticket of a cancelled order
+type cancellation struct {
+	invoiceRemoved bool
+	refundStatus   string
+	syrveCancelled bool
+}
+
+// CancelOrder cancels the order together with everything attached to it: an unpaid invoice is
+// invalidated, a paid one is refunded, the Syrve order is cancelled and reserved stock released.
+// The cancellation is claimed before the money moves and the order row stays locked until
+// the invoice is dealt with, when that fails the order stays as it was. A no-show is counted
+// in the history of the customer's phone.
+func (s *OrderService) CancelOrder(ctx context.Context, id uuid.UUID, changedBy, reason string, noShow bool) (*models.Order, error) {
+	order, err := s.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.StatusID.CanTransitionTo(models.StatusCancelled, order.PaymentMethod) {
+		return nil, fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, order.StatusID, models.StatusCancelled)
+	}
+
+	var done cancellation
+	sentToKitchen := order.SyrveNotified || (!order.Scheduled && readyForKitchen(order))
+	paid := order.StatusID != models.StatusPending
+
+	err = s.transitionStatus(ctx, order, models.StatusCancelled, changedBy, reason, func(tx *sqlx.Tx) error {
+		if err := s.returnPayment(order, paid, &done); err != nil {
+			return err
+		}
+		if done.refundStatus != "" {
+			if err := s.repository.WithTx(tx).SetRefundStatus(ctx, order.ID, done.refundStatus); err != nil {
+				return err
+			}
+			order.RefundStatus = &done.refundStatus
+		}
+
+		if noShow {
+			if err := s.repository.WithTx(tx).SetNoShow(ctx, order.ID); err != nil {
+				return err
+			}
+			order.NoShow = true
+		}
+
+		txOutbox := s.outboxRepository.WithTx(tx)
+		if sentToKitchen {
+			if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueSyrveCancel, OrderCancelEvent{OrderID: order.ID, Reason: reason}); err != nil {
+				return err
+			}
+			done.syrveCancelled = true
+		}
+
+		return enqueueEvent(ctx, txOutbox, rabbitmq.QueueTelegram, cancelNotificationPayload(order, reason, done))
+	})
+	if err != nil {
+		if done.refundStatus != "" || done.invoiceRemoved {
+			log.Printf("payment of order %s was returned but the cancellation failed: %v", order.ID, err)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// returnPayment invalidates the invoice of an unpaid online order or refunds a paid one
+func (s *OrderService) returnPayment(order *models.Order, paid bool, done *cancellation) error {
+	if order.PaymentMethod != "online" || order.InvoiceID == nil || *order.InvoiceID == "" {
+		return nil
+	}
+
+	if !paid {
+		if err := s.paymentClient.CancelPayment(*order.InvoiceID); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvoiceCancelFailed, err)
+		}
+		done.invoiceRemoved = true
+		return nil
+	}
+
+	refund, err := s.paymentClient.RefundPayment(*order.InvoiceID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+	done.refundStatus = refund.Status
+	return nil
+}
+
+// cancelNotificationPayload is the Telegram message about a cancelled order
+func cancelNotificationPayload(order *models.Order, reason string, done cancellation) map[string]interface{} {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(
+		"🚫 Замовлення #%s скасовано\n%s, %s, %.0f ₴",
+		strings.ToUpper(order.ID.String()[:8]),
+		html.EscapeString(order.Name),
+		order.Phone,
+		order.TotalPrice,
+	))
+	if reason != "" {
+		sb.WriteString(fmt.Sprintf("\nПричина: %s", html.EscapeString(reason)))
+	}
+	if order.NoShow {
+		sb.WriteString("\n🙅 Клієнт не забрав замовлення")
+	}
+	if done.refundStatus != "" {
+		sb.WriteString(fmt.Sprintf("\n💸 Кошти повертаються клієнту (%s)", done.refundStatus))
+	}
+	if done.invoiceRemoved {
+		sb.WriteString("\nРахунок на оплату анульовано")
+	}
+	if done.syrveCancelled {
+		sb.WriteString("\nЗамовлення скасовується в Syrve")
+	}
+
+	chatIDStr := helpers.GetEnv("TELEGRAM_CHAT_ID", "0")
+	chatID, _ := strconv.ParseInt(chatIDStr, 10, 64)
+
+	return map[string]interface{}{
+		"chat_id": chatID,
+		"text":    sb.String(),
+	}
+}
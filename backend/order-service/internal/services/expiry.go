@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/helpers"
+	"github.com/tonysanin/brobar/pkg/rabbitmq"
+)
+
+// StatusChangedBySystem marks history entries written by background jobs
+const StatusChangedBySystem = "system"
+
+// expiryGrace gives webhooks sent right before the invoice expired time to arrive
+const expiryGrace = 2 * time.Minute
+
+// RunPaymentExpiry cancels expired unpaid orders every interval until ctx is done
+func (s *OrderService) RunPaymentExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireUnpaidOrders(ctx, time.Now()); err != nil {
+				log.Printf("failed to expire unpaid orders: %v", err)
+			}
+		}
+	}
+}
+
+// ExpireUnpaidOrders cancels pending online orders whose invoice validity has passed,
+// invalidates their invoices and releases reserved stock. An order whose invoice can't be
+// invalidated stays pending until the next run, it could still be paid. Returns the cancelled orders.
+func (s *OrderService) ExpireUnpaidOrders(ctx context.Context, now time.Time) ([]models.Order, error) {
+	orders, err := s.repository.GetUnpaidOnlineOrders(ctx, now.Add(-s.paymentTTL-expiryGrace))
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []models.Order
+	for i := range orders {
+		order := &orders[i]
+
+		var done cancellation
+		err := s.transitionStatus(ctx, order, models.StatusCancelled, StatusChangedBySystem, "payment timeout", func(tx *sqlx.Tx) error {
+			if err := s.returnPayment(order, false, &done); err != nil {
+				return err
+			}
+			return enqueueEvent(ctx, s.outboxRepository.WithTx(tx), rabbitmq.QueueTelegram, expiryNotificationPayload(order))
+		})
+		if err != nil {
+			// Paid or changed by an admin in the meantime
+			if errors.Is(err, ErrStatusConflict) {
+				continue
+			}
+			log.Printf("failed to cancel unpaid order %s: %v", order.ID, err)
+			continue
+		}
+
+		expired = append(expired, *order)
+	}
+
+	return expired, nil
+}
+
+// expiryNotificationPayload is the Telegram message about an order cancelled for non-payment
+func expiryNotificationPayload(order *models.Order) map[string]interface{} {
+	msgText := fmt.Sprintf(
+		"⌛ Замовлення #%s скасовано: не оплачено вчасно\n%s, %s, %.0f ₴",
+		strings.ToUpper(order.ID.String()[:8]),
+		html.EscapeString(order.Name),
+		order.Phone,
+		order.TotalPrice,
+	)
+
+	chatIDStr := helpers.GetEnv("TELEGRAM_CHAT_ID", "0")
+	chatID, _ := strconv.ParseInt(chatIDStr, 10, 64)
+
+	return map[string]interface{}{
+		"chat_id": chatID,
+		"text":    msgText,
+	}
+}
+
+// refundManual is the refund status of a payment the staff were asked to return by hand
+const refundManual = "manual"
+
+// refundLatePayment returns a payment made for an order that was already cancelled. When the
+// refund fails the staff are asked to return the money by hand. The outcome is saved on the
+// order with the notification, so a redelivered webhook or one for an order refunded on
+// cancellation neither refunds nor alerts again.
+func (s *OrderService) refundLatePayment(ctx context.Context, order *models.Order, invoiceID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	txRepository := s.repository.WithTx(tx)
+	status, err := txRepository.LockRefundStatus(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if status != nil {
+		log.Printf("payment %s of cancelled order %s already handled (%s)", invoiceID, order.ID, *status)
+		return nil
+	}
+
+	refundStatus := refundManual
+	refund, err := s.paymentClient.RefundPayment(invoiceID)
+	if err != nil {
+		log.Printf("failed to refund late payment %s of cancelled order %s: %v", invoiceID, order.ID, err)
+	} else {
+		refundStatus = refund.Status
+	}
+	if err := txRepository.SetRefundStatus(ctx, order.ID, refundStatus); err != nil {
+		return err
+	}
+
+	msgText := fmt.Sprintf(
+		"💸 Надійшла оплата за скасоване замовлення #%s (%s, %s, %.0f ₴)",
+		strings.ToUpper(order.ID.String()[:8]),
+		html.EscapeString(order.Name),
+		order.Phone,
+		order.TotalPrice,
+	)
+	if err != nil {
+		msgText += "\n❗ Повернути кошти автоматично не вдалося, поверніть їх вручну"
+	} else {
+		msgText += fmt.Sprintf("\nКошти повертаються клієнту (%s)", refund.Status)
+	}
+
+	chatIDStr := helpers.GetEnv("TELEGRAM_CHAT_ID", "0")
+	chatID, _ := strconv.ParseInt(chatIDStr, 10, 64)
+
+	if err := enqueueEvent(ctx, s.outboxRepository.WithTx(tx), rabbitmq.QueueTelegram, map[string]interface{}{
+		"chat_id": chatID,
+		"text":    msgText,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
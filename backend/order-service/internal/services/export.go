@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/export"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// exportBatchSize is how many orders are loaded (with their items) at a time
+const exportBatchSize = 200
+
+var exportHeader = []interface{}{
+	"Order ID", "Created At", "Time", "Status", "Delivery Type", "Payment Method", "Invoice ID", "Zone",
+	"Name", "Phone", "Address", "Promo", "Discount", "Delivery Cost", "Delivery Door Price", "Order Total",
+	"Item", "Item External ID", "Quantity", "Price", "Item Total", "Item Discount",
+}
+
+// ExportOrders writes the filtered orders as a table with one row per order item,
+// the order columns are repeated on every row of the order
+func (s *OrderService) ExportOrders(ctx context.Context, filter models.OrderFilter, format export.Format, w io.Writer) error {
+	writer, err := export.NewWriter(format, w, "Orders")
+	if err != nil {
+		return err
+	}
+
+	if err := writer.WriteRow(exportHeader); err != nil {
+		return err
+	}
+
+	err = s.repository.ForEachOrderBatch(ctx, filter, exportBatchSize, func(orders []models.Order) error {
+		orderIDs := make([]uuid.UUID, len(orders))
+		for i, order := range orders {
+			orderIDs[i] = order.ID
+		}
+
+		items, err := s.orderItemRepository.GetOrderItemsByOrderIDs(ctx, orderIDs)
+		if err != nil {
+			return fmt.Errorf("failed to fetch order items: %w", err)
+		}
+		itemsByOrderID := make(map[uuid.UUID][]models.OrderItem)
+		for _, item := range items {
+			itemsByOrderID[item.OrderID] = append(itemsByOrderID[item.OrderID], item)
+		}
+
+		for i := range orders {
+			order := &orders[i]
+			orderCells := s.exportOrderCells(order)
+
+			orderItems := itemsByOrderID[order.ID]
+			if len(orderItems) == 0 {
+				if err := writer.WriteRow(orderCells); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, item := range orderItems {
+				row := append(orderCells[:len(orderCells):len(orderCells)],
+					item.Name, item.ExternalProductID, item.Quantity, item.Price, item.TotalPrice, item.Discount,
+				)
+				if err := writer.WriteRow(row); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func (s *OrderService) exportOrderCells(order *models.Order) []interface{} {
+	var invoiceID, zone string
+	if order.InvoiceID != nil {
+		invoiceID = *order.InvoiceID
+	}
+	if order.Zone != nil {
+		zone = *order.Zone
+	}
+
+	return []interface{}{
+		order.ID.String(),
+		order.CreatedAt.In(s.location),
+		order.Time.In(s.location),
+		string(order.StatusID),
+		string(order.DeliveryTypeID),
+		order.PaymentMethod,
+		invoiceID,
+		zone,
+		order.Name,
+		order.Phone,
+		order.Address,
+		order.Promo,
+		order.Discount,
+		order.DeliveryCost,
+		order.DeliveryDoorPrice,
+		order.TotalPrice,
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+// idempotencyLease is how long a claim without a stored response holds its key. It outlasts
+// creating an order, so only a claim whose request died is taken over.
+const idempotencyLease = 2 * time.Minute
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("ключ ідемпотентності вже використано для іншого замовлення")
+	ErrIdempotencyKeyInFlight = errors.New("замовлення з цим ключем вже обробляється")
+)
+
+type IdempotencyService struct {
+	repository   *repositories.IdempotencyRepository
+	orderService *OrderService
+	ttl          time.Duration
+}
+
+func NewIdempotencyService(repository *repositories.IdempotencyRepository, orderService *OrderService, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repository:   repository,
+		orderService: orderService,
+		ttl:          ttl,
+	}
+}
+
+// CreateOrder creates the order once per key. Retries with the same key and payload
+// within the TTL get the original order back (replayed = true) instead of a new one.
+// The response is stored in the transaction that saves the order, so a key is never left
+// unfinished for an existing order. Failed attempts free the key so the client can retry
+// after fixing the request, a crashed one holds it for idempotencyLease.
+func (s *IdempotencyService) CreateOrder(ctx context.Context, key string, input *CreateOrderInput) (order *models.Order, replayed bool, err error) {
+	requestHash, err := hashOrderInput(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	claimed, err := s.repository.ClaimKey(ctx, key, requestHash, now.Add(-s.ttl), now.Add(-idempotencyLease))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !claimed {
+		order, err := s.replay(ctx, key, requestHash)
+		return order, err == nil, err
+	}
+
+	input.saved = func(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+		response, err := json.Marshal(order)
+		if err != nil {
+			return fmt.Errorf("failed to marshal order: %w", err)
+		}
+		return s.repository.WithTx(tx).CompleteKey(ctx, key, order.ID, response)
+	}
+
+	order, err = s.orderService.CreateOrderFromInput(ctx, input)
+	if err != nil {
+		if delErr := s.repository.DeleteKey(ctx, key); delErr != nil {
+			log.Printf("failed to free idempotency key %s: %v", key, delErr)
+		}
+		return nil, false, err
+	}
+
+	return order, false, nil
+}
+
+func (s *IdempotencyService) replay(ctx context.Context, key, requestHash string) (*models.Order, error) {
+	existing, err := s.repository.GetKey(ctx, key)
+	if err != nil {
+		// Freed by a failed attempt between our claim and read
+		if errors.Is(err, customerrors.IdempotencyKeyNotFound) {
+			return nil, ErrIdempotencyKeyInFlight
+		}
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.Response == nil {
+		return nil, ErrIdempotencyKeyInFlight
+	}
+
+	var order models.Order
+	if err := json.Unmarshal(existing.Response, &order); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stored order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// RunCleanup drops keys older than the TTL every interval until ctx is done
+func (s *IdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.repository.DeleteExpiredKeys(ctx, time.Now().Add(-s.ttl)); err != nil {
+				log.Printf("failed to clean up idempotency keys: %v", err)
+			}
+		}
+	}
+}
+
+func hashOrderInput(input *CreateOrderInput) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal order input: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
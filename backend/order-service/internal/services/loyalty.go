@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+// OutboxLoyalty carries points ledger operations to user-service. The outbox relay delivers
+// them itself, so a failed call is retried with backoff like any other event.
+const OutboxLoyalty OutboxHandlerKey = "loyalty_ledger"
+
+const (
+	loyaltyEarn    = "earn"
+	loyaltyReverse = "reverse"
+	loyaltyRelease = "release" // reverse unless the order was saved
+)
+
+// loyaltyRedeemGuard is how long after a redemption its order must be saved, otherwise the
+// points are given back. It outlasts creating an order.
+const loyaltyRedeemGuard = 5 * time.Minute
+
+// LoyaltyEvent is a ledger operation for an order of a signed-in customer. user-service
+// applies each operation once per order, so delivering it again changes nothing.
+type LoyaltyEvent struct {
+	Operation string    `json:"operation"`
+	UserID    uuid.UUID `json:"user_id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Amount    float64   `json:"amount,omitempty"` // paid for the items, points are earned on it
+}
+
+var (
+	ErrLoyaltyDisabled       = errors.New("оплата бонусами зараз недоступна")
+	ErrLoyaltySignInRequired = errors.New("щоб оплатити бонусами, увійдіть до облікового запису")
+	ErrLoyaltyCapExceeded    = errors.New("бонусами можна оплатити лише частину замовлення")
+	ErrLoyaltyInsufficient   = errors.New("на рахунку недостатньо бонусів")
+)
+
+// GetLoyaltySettings returns the loyalty program rules
+func (s *ValidationService) GetLoyaltySettings() (*clients.LoyaltySettings, error) {
+	settings, err := s.webClient.GetLoyaltySettings()
+	if err != nil {
+		return nil, fmt.Errorf("не вдалося отримати налаштування бонусів: %w", err)
+	}
+	return settings, nil
+}
+
+// applyLoyaltyPoints pays part of the order with points, at most the configured share of
+// the discounted items total. One point is one hryvnia.
+func (s *OrderService) applyLoyaltyPoints(pricing *OrderPricing, points int) error {
+	settings, err := s.validationService.GetLoyaltySettings()
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		return ErrLoyaltyDisabled
+	}
+
+	limit := int(math.Floor((pricing.ItemsTotal - pricing.Discount) * settings.MaxRedeemPercent / 100))
+	if points > limit {
+		return fmt.Errorf("%w (не більше %d ₴)", ErrLoyaltyCapExceeded, limit)
+	}
+
+	pricing.LoyaltyPoints = points
+	return nil
+}
+
+// redeemLoyaltyPoints debits the points the order is paid with from the customer's balance.
+// A release is written first, so the points come back if the order never gets saved, even
+// when the process dies before it could say so.
+func (s *OrderService) redeemLoyaltyPoints(ctx context.Context, order *models.Order) error {
+	if order.UserID == nil || order.LoyaltyPoints == 0 {
+		return nil
+	}
+
+	event := LoyaltyEvent{Operation: loyaltyRelease, UserID: *order.UserID, OrderID: order.ID}
+	if err := enqueueHandlerEvent(ctx, s.outboxRepository, OutboxLoyalty, event, time.Now().Add(loyaltyRedeemGuard)); err != nil {
+		return err
+	}
+
+	if err := s.userClient.RedeemPoints(*order.UserID, order.ID, order.LoyaltyPoints); err != nil {
+		if errors.Is(err, clients.ErrInsufficientPoints) {
+			return ErrLoyaltyInsufficient
+		}
+		return err
+	}
+
+	return nil
+}
+
+// releaseLoyaltyPoints gives the redeemed points of an order that could not be saved back
+// through the outbox, so a failed call is retried
+func (s *OrderService) releaseLoyaltyPoints(ctx context.Context, order *models.Order) {
+	if order.UserID == nil || order.LoyaltyPoints == 0 {
+		return
+	}
+
+	event := LoyaltyEvent{Operation: loyaltyRelease, UserID: *order.UserID, OrderID: order.ID}
+	if err := enqueueHandlerEvent(ctx, s.outboxRepository, OutboxLoyalty, event, time.Time{}); err != nil {
+		log.Printf("failed to release loyalty points of order %s, they are released in %s: %v", order.ID, loyaltyRedeemGuard, err)
+	}
+}
+
+// enqueueLoyaltyEvent writes the ledger operation the order's new status calls for: a completed
+// order earns points for what was paid for the items, delivery does not earn, and a cancelled
+// one gets its redeemed points back. Pass a transactional outbox to tie it to the status change.
+func enqueueLoyaltyEvent(ctx context.Context, outbox *repositories.OutboxRepository, order *models.Order) error {
+	if order.UserID == nil {
+		return nil
+	}
+
+	event := LoyaltyEvent{UserID: *order.UserID, OrderID: order.ID}
+	switch order.StatusID {
+	case models.StatusCompleted:
+		event.Operation = loyaltyEarn
+		event.Amount = order.TotalPrice - order.DeliveryCost - order.DeliveryDoorPrice
+	case models.StatusCancelled:
+		if order.LoyaltyPoints == 0 {
+			return nil
+		}
+		event.Operation = loyaltyReverse
+	default:
+		return nil
+	}
+
+	return enqueueHandlerEvent(ctx, outbox, OutboxLoyalty, event, time.Time{})
+}
+
+// DeliverLoyaltyEvent applies a ledger operation written by enqueueLoyaltyEvent, an error
+// makes the outbox relay try again later
+func (s *OrderService) DeliverLoyaltyEvent(ctx context.Context, payload string) error {
+	var event LoyaltyEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Printf("dropping malformed loyalty event: %v", err)
+		return nil
+	}
+
+	switch event.Operation {
+	case loyaltyEarn:
+		settings, err := s.validationService.GetLoyaltySettings()
+		if err != nil {
+			return err
+		}
+		if !settings.Enabled {
+			return nil
+		}
+
+		points := int(math.Floor(event.Amount * settings.EarnRate))
+		if points <= 0 {
+			return nil
+		}
+		return s.userClient.EarnPoints(event.UserID, event.OrderID, points)
+	case loyaltyReverse:
+		return s.userClient.ReversePoints(event.UserID, event.OrderID)
+	case loyaltyRelease:
+		_, err := s.repository.GetOrderById(ctx, event.OrderID)
+		if err == nil {
+			// Saved, a cancellation reverses the points
+			return nil
+		}
+		if !errors.Is(err, customerrors.OrderNotFound) {
+			return err
+		}
+		return s.userClient.ReversePoints(event.UserID, event.OrderID)
+	default:
+		log.Printf("dropping loyalty event with unknown operation %q", event.Operation)
+		return nil
+	}
+}
+
+// loyaltyShares spreads the redeemed points over the item lines in proportion to what is
+// left to pay for each, the last line takes the rounding remainder
+func loyaltyShares(items []models.OrderItem, points int) []float64 {
+	shares := make([]float64, len(items))
+	if points == 0 {
+		return shares
+	}
+
+	var total float64
+	for _, item := range items {
+		total += item.TotalPrice - item.Discount
+	}
+	if total <= 0 {
+		return shares
+	}
+
+	remaining := float64(points)
+	for i, item := range items {
+		share := roundMoney(float64(points) * (item.TotalPrice - item.Discount) / total)
+		if i == len(items)-1 {
+			share = roundMoney(remaining)
+		}
+		shares[i] = share
+		remaining -= share
+	}
+
+	return shares
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+func TestLoyaltyShares(t *testing.T) {
+	tests := []struct {
+		name   string
+		items  []models.OrderItem
+		points int
+		want   []float64
+	}{
+		{
+			name:   "no points",
+			items:  []models.OrderItem{{TotalPrice: 100}, {TotalPrice: 50}},
+			points: 0,
+			want:   []float64{0, 0},
+		},
+		{
+			name:   "proportional to the price",
+			items:  []models.OrderItem{{TotalPrice: 300}, {TotalPrice: 100}},
+			points: 40,
+			want:   []float64{30, 10},
+		},
+		{
+			name:   "promo discount taken into account",
+			items:  []models.OrderItem{{TotalPrice: 300, Discount: 100}, {TotalPrice: 200}},
+			points: 40,
+			want:   []float64{20, 20},
+		},
+		{
+			name:   "last line takes the rounding remainder",
+			items:  []models.OrderItem{{TotalPrice: 100}, {TotalPrice: 100}, {TotalPrice: 100}},
+			points: 10,
+			want:   []float64{3.33, 3.33, 3.34},
+		},
+		{
+			name:   "fully discounted cart",
+			items:  []models.OrderItem{{TotalPrice: 100, Discount: 100}},
+			points: 10,
+			want:   []float64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := loyaltyShares(tt.items, tt.points)
+			assert.Len(t, shares, len(tt.want))
+			for i, want := range tt.want {
+				assert.InDelta(t, want, shares[i], 0.001, "item %d", i)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"html"
+	"log"
 	"math"
 	"net/url"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/tonysanin/brobar/order-service/internal/clients"
 	"github.com/tonysanin/brobar/order-service/internal/models"
 	"github.com/tonysanin/brobar/order-service/internal/repositories"
@@ -22,23 +24,39 @@ import (
 )
 
 type OrderService struct {
-	repository          *repositories.OrderRepository
-	orderItemRepository *repositories.OrderItemRepository
-	productClient       *clients.ProductClient
-	paymentClient       *payment.Client
-	validationService   *ValidationService
-	producer            *rabbitmq.Producer
-	location            *time.Location
+	db                      *sqlx.DB
+	repository              *repositories.OrderRepository
+	orderItemRepository     *repositories.OrderItemRepository
+	statusHistoryRepository *repositories.StatusHistoryRepository
+	productClient           *clients.ProductClient
+	paymentClient           *payment.Client
+	userClient              *clients.UserClient
+	validationService       *ValidationService
+	promoService            *PromoService
+	riskService             *RiskService
+	quoteSigner             *QuoteSigner
+	outboxRepository        *repositories.OutboxRepository
+	location                *time.Location
+	paymentTTL              time.Duration
+	prepLeadTimes           map[models.DeliveryType]time.Duration
 }
 
 func NewOrderService(
+	db *sqlx.DB,
 	repository *repositories.OrderRepository,
 	orderItemRepository *repositories.OrderItemRepository,
+	statusHistoryRepository *repositories.StatusHistoryRepository,
 	productClient *clients.ProductClient,
 	paymentClient *payment.Client,
+	userClient *clients.UserClient,
 	validationService *ValidationService,
-	producer *rabbitmq.Producer,
+	promoService *PromoService,
+	riskService *RiskService,
+	quoteSigner *QuoteSigner,
+	outboxRepository *repositories.OutboxRepository,
 	timezone string,
+	paymentTTL time.Duration,
+	prepLeadTimes map[models.DeliveryType]time.Duration,
 ) *OrderService {
 	loc, err := time.LoadLocation(timezone)
 	if err != nil {
@@ -46,46 +64,65 @@ func NewOrderService(
 	}
 
 	return &OrderService{
-		repository:          repository,
-		orderItemRepository: orderItemRepository,
-		productClient:       productClient,
-		paymentClient:       paymentClient,
-		validationService:   validationService,
-		producer:            producer,
-		location:            loc,
+		db:                      db,
+		repository:              repository,
+		orderItemRepository:     orderItemRepository,
+		statusHistoryRepository: statusHistoryRepository,
+		productClient:           productClient,
+		paymentClient:           paymentClient,
+		userClient:              userClient,
+		validationService:       validationService,
+		promoService:            promoService,
+		riskService:             riskService,
+		quoteSigner:             quoteSigner,
+		outboxRepository:        outboxRepository,
+		location:                loc,
+		paymentTTL:              paymentTTL,
+		prepLeadTimes:           prepLeadTimes,
 	}
 }
 
+// Location is the business timezone used for date-only values
+func (s *OrderService) Location() *time.Location {
+	return s.location
+}
+
 // OrderItemInput represents minimal item data from frontend
 type OrderItemInput struct {
 	ProductID          uuid.UUID
-	ProductVariationID *uuid.UUID
+	ProductVariationID *uuid.UUID // legacy single selection, used when Variations is empty
+	Variations         []VariationSelection
 	Quantity           int
 }
 
 // CreateOrderInput represents minimal order data from frontend
 type CreateOrderInput struct {
-	Name           string
-	Phone          string
-	Email          string
-	DeliveryTypeID string
-	Address        string
-	Zone           string
-	Entrance       string
-	DeliveryDoor   bool
-	Coords         string
-	Time           string
-	PaymentMethod  string
-	Cutlery        int
-	PromoCode      string
-	Wishes         string
-	Items          []OrderItemInput
-	ClientTotal    float64
+	PricingInput
+	Name          string
+	Email         string
+	Address       string
+	Zone          string
+	Entrance      string
+	Time          string
+	PaymentMethod string
+	Cutlery       int
+	Wishes        string
+	ClientTotal   float64
+	QuoteToken    string
+	UserID        *uuid.UUID // signed-in customer, nil for guest checkout
+
+	// saved runs in the transaction that saves the order, it may be nil
+	saved func(ctx context.Context, tx *sqlx.Tx, order *models.Order) error
 }
 
 func (s *OrderService) CreateOrderFromInput(ctx context.Context, input *CreateOrderInput) (*models.Order, error) {
+	// 0. Reject orders while sales are paused
+	if err := s.validationService.ValidateSalesOpen(s.location); err != nil {
+		return nil, err
+	}
+
 	// 1. Validate time
-	if err := s.validationService.ValidateOrderTime(input.Time, input.DeliveryTypeID); err != nil {
+	if err := s.validationService.ValidateOrderTime(input.Time, input.DeliveryTypeID, s.location); err != nil {
 		return nil, err
 	}
 
@@ -96,86 +133,52 @@ func (s *OrderService) CreateOrderFromInput(ctx context.Context, input *CreateOr
 	}
 	input.PaymentMethod = normalizedPayment
 
-	// 2. Fetch products and build order items with actual prices
-	var items []models.OrderItem
-	var itemsTotal float64 = 0
-
-	for _, itemInput := range input.Items {
-		product, err := s.productClient.GetProduct(itemInput.ProductID)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %s", ErrProductNotFound, itemInput.ProductID.String())
-		}
-
-		// Validation: Check stock
-		// If stock is nil, it means unlimited
-		if product.Stock != nil {
-			if float64(itemInput.Quantity) > *product.Stock {
-				return nil, fmt.Errorf("товар %s закінчився (доступно: %.0f, бажано: %d)", product.Name, *product.Stock, itemInput.Quantity)
-			}
-		}
-
-		item := models.OrderItem{
-			ID:                uuid.New(),
-			ProductID:         itemInput.ProductID,
-			ExternalProductID: product.ExternalID,
-			Quantity:          itemInput.Quantity,
-			// Use product price/weight (variations don't have separate prices in this system)
-			Price:  product.Price,
-			Weight: product.Weight,
-		}
+	// 1.2 Store the phone in E.164, flagged phones must pay online or can't order at all
+	if input.Phone, err = normalizePhone(input.Phone); err != nil {
+		return nil, err
+	}
+	risk, err := s.riskService.GetPhoneRisk(ctx, input.Phone, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPhoneRisk(risk, input.PaymentMethod); err != nil {
+		return nil, err
+	}
 
-		// If variation is specified, fetch variation and group info
-		if itemInput.ProductVariationID != nil {
-			variation, err := s.productClient.GetVariation(*itemInput.ProductVariationID)
-			if err != nil {
-				return nil, fmt.Errorf("варіація не знайдена: %s", itemInput.ProductVariationID.String())
-			}
+	// 1.3 Points are redeemed from the account of a signed-in customer only
+	if input.LoyaltyPoints > 0 && input.UserID == nil {
+		return nil, ErrLoyaltySignInRequired
+	}
 
-			// Fetch variation group for the group name
-			group, err := s.productClient.GetVariationGroup(variation.GroupID)
-			if err == nil && group != nil {
-				item.ProductVariationGroupID = &group.ID
-				item.ProductVariationGroupName = &group.Name
-			}
+	// 2. Price the cart: products, promo, loyalty points, delivery
+	pricing, err := s.priceOrder(ctx, &input.PricingInput)
+	if err != nil {
+		return nil, err
+	}
+	if pricing.Promo != nil {
+		input.PromoCode = pricing.Promo.Code
+	}
 
-			item.ProductVariationID = itemInput.ProductVariationID
-			item.ProductVariationExternalID = &variation.ExternalID
-			item.ProductVariationName = &variation.Name
-			item.Name = fmt.Sprintf("%s (%s)", product.Name, variation.Name)
-		} else {
-			item.Name = product.Name
+	// 2.1 Zone rules: payment methods and extra prep time (the minimum is checked with the delivery cost)
+	var zonePrepTime time.Duration
+	if zone := pricing.Zone; zone != nil {
+		if !zone.AllowsPaymentMethod(input.PaymentMethod) {
+			return nil, fmt.Errorf("%w (%s)", ErrPaymentMethodNotInZone, zone.Name)
 		}
-
-		item.TotalPrice = item.Price * float64(item.Quantity)
-		item.TotalWeight = item.Weight * float64(item.Quantity)
-
-		itemsTotal += item.TotalPrice
-		items = append(items, item)
+		zonePrepTime = zone.PrepTime()
 	}
 
-	// 3. Calculate delivery cost by coordinates (now we have itemsTotal for free delivery check)
-	var deliveryCost float64 = 0
-	var deliveryDoorPrice float64 = 0
-	var zoneName string
-
-	if input.DeliveryTypeID == "delivery" && input.Coords != "" {
-		cost, doorPrice, zone, err := s.validationService.GetDeliveryCost(input.Coords, input.DeliveryDoor, itemsTotal)
+	// 3. Compare totals against the quote or the client calculation
+	if input.QuoteToken != "" {
+		quote, err := s.quoteSigner.Verify(input.QuoteToken, &input.PricingInput, time.Now())
 		if err != nil {
 			return nil, err
 		}
-		deliveryCost = cost
-		deliveryDoorPrice = doorPrice
-		if zone != nil {
-			zoneName = zone.Name
+		if math.Abs(pricing.Total-quote.Total) > 0.01 {
+			return nil, fmt.Errorf("%w (очікувано: %.2f, у розрахунку: %.2f)", ErrPriceMismatch, pricing.Total, quote.Total)
 		}
-	}
-
-	// 4. Calculate server total
-	serverTotal := itemsTotal + deliveryCost + deliveryDoorPrice
-
-	// 4. Compare totals (allow small difference for rounding)
-	if math.Abs(serverTotal-input.ClientTotal) > 1.0 {
-		return nil, fmt.Errorf("%w (очікувано: %.2f, отримано: %.2f)", ErrPriceMismatch, serverTotal, input.ClientTotal)
+	} else if math.Abs(pricing.Total-input.ClientTotal) > 1.0 {
+		return nil, fmt.Errorf("%w (очікувано: %.2f, отримано: %.2f)", ErrPriceMismatch, pricing.Total, input.ClientTotal)
 	}
 
 	// 5. Parse time
@@ -188,13 +191,24 @@ func (s *OrderService) CreateOrderFromInput(ctx context.Context, input *CreateOr
 			return nil, fmt.Errorf("невірний формат часу")
 		}
 		orderTime = parsed
+
+		// Far zones need more time than the working hours allow for
+		if earliest := time.Now().Add(zonePrepTime); orderTime.Before(earliest) {
+			return nil, fmt.Errorf("%w: для вашої зони найближчий час %s", ErrTimeNotAvailable, earliest.In(s.location).Format("15:04"))
+		}
 	}
 
 	// 6. Create order
+	trackingToken, err := newTrackingToken()
+	if err != nil {
+		return nil, err
+	}
+
 	order := &models.Order{
 		ID:                uuid.New(),
+		UserID:            input.UserID,
 		StatusID:          models.StatusPending,
-		TotalPrice:        serverTotal,
+		TotalPrice:        pricing.Total,
 		CreatedAt:         time.Now(),
 		UpdatedAt:         time.Now(),
 		Name:              input.Name,
@@ -202,33 +216,61 @@ func (s *OrderService) CreateOrderFromInput(ctx context.Context, input *CreateOr
 		Email:             input.Email,
 		Address:           input.Address,
 		Entrance:          input.Entrance,
-		Zone:              &zoneName,
+		Zone:              &pricing.ZoneName,
 		Coords:            input.Coords,
 		Time:              orderTime,
 		PaymentMethod:     input.PaymentMethod,
 		Cutlery:           input.Cutlery,
 		Promo:             input.PromoCode,
 		Wishes:            input.Wishes,
-		DeliveryCost:      deliveryCost,
+		DeliveryCost:      pricing.DeliveryCost,
 		DeliveryDoor:      input.DeliveryDoor,
-		DeliveryDoorPrice: deliveryDoorPrice,
+		DeliveryDoorPrice: pricing.DeliveryDoorPrice,
 		DeliveryTypeID:    models.DeliveryType(input.DeliveryTypeID),
-		Items:             items,
+		Discount:          pricing.Discount,
+		LoyaltyPoints:     pricing.LoyaltyPoints,
+		TrackingToken:     &trackingToken,
+		Items:             pricing.Items,
+	}
+
+	// 6.0 Pre-orders wait until the kitchen has to start on them
+	s.holdIfScheduled(order, time.Now(), zonePrepTime)
+
+	// 6.1 Reject times whose slot is already full
+	capacity, err := s.validationService.GetSlotCapacity()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSlotCapacity(ctx, s.repository, capacity, order); err != nil {
+		return nil, err
+	}
+
+	// 6.2 Reserve stock so concurrent orders can't buy the same last portion
+	if err := s.reserveStock(order); err != nil {
+		return nil, err
+	}
+
+	// 6.3 Debit the loyalty points the order is paid with
+	if err := s.redeemLoyaltyPoints(ctx, order); err != nil {
+		s.releaseStock(order.ID)
+		return nil, err
 	}
 
-	// 7. Payment Initialization
 	// 7. Payment Initialization
 	if input.PaymentMethod == "online" {
 		params := payment.InitPaymentInput{
-			Amount:      int(serverTotal * 100),
+			Amount:      int(pricing.Total * 100),
 			OrderID:     order.ID.String(),
-			RedirectURL: fmt.Sprintf("https://%s/order/success", helpers.GetEnv("NGINX_DOMAIN", "brobar.delivery")),
+			RedirectURL: trackingURL(trackingToken),
 			WebhookURL:  fmt.Sprintf("https://%s/api/payment-service/webhooks/monobank", helpers.GetEnv("NGINX_DOMAIN", "brobar.delivery")),
+			Validity:    int(s.paymentTTL.Seconds()),
 			Basket:      s.getBasketOrders(order),
 		}
 
 		output, err := s.paymentClient.InitPayment(params)
 		if err != nil {
+			s.releaseStock(order.ID)
+			s.releaseLoyaltyPoints(ctx, order)
 			return nil, fmt.Errorf("failed to init payment: %w", err)
 		}
 
@@ -237,30 +279,92 @@ func (s *OrderService) CreateOrderFromInput(ctx context.Context, input *CreateOr
 		order.PaymentURL = output.PaymentURL
 	}
 
-	// 8. Save to database
+	// 8. Save the order together with its notifications, so they can't get lost
 	for i := range order.Items {
 		order.Items[i].OrderID = order.ID
 	}
 
-	if err := s.repository.CreateOrder(ctx, order); err != nil {
+	// Send to Syrve right away if payment doesn't require confirmation (cash/terminal only),
+	// held pre-orders are sent by the scheduled release
+	confirmed := input.PaymentMethod == "cash"
+	sendToSyrve := confirmed && !order.Scheduled
+
+	if err := s.saveNewOrder(ctx, order, capacity, pricing.Promo, sendToSyrve, risk, input.saved); err != nil {
+		s.releaseStock(order.ID)
+		s.releaseLoyaltyPoints(ctx, order)
+		if order.InvoiceID != nil {
+			if cancelErr := s.paymentClient.CancelPayment(*order.InvoiceID); cancelErr != nil {
+				log.Printf("failed to cancel invoice %s of unsaved order %s: %v", *order.InvoiceID, order.ID, cancelErr)
+			}
+		}
 		return nil, err
 	}
 
-	if err := s.orderItemRepository.CreateOrderItems(ctx, order.Items); err != nil {
-		return nil, err
+	if confirmed {
+		s.commitStock(order.ID)
 	}
 
-	// 9. Send notification
-	go s.sendOrderNotification(order)
+	return order, nil
+}
 
-	// 10. Send to Syrve if payment doesn't require confirmation (cash/terminal only)
-	// 10. Send to Syrve if payment doesn't require confirmation (cash/terminal only)
-	if input.PaymentMethod == "cash" {
-		orderJSON, _ := json.Marshal(order)
-		_ = s.producer.SendMessage(rabbitmq.QueueSyrve, string(orderJSON))
+// saveNewOrder writes the order, its items, the promo code usage, the first status history entry
+// and the outgoing events in one transaction, together with whatever saved writes. The slot
+// capacity and the promo limits are checked again under their locks.
+func (s *OrderService) saveNewOrder(ctx context.Context, order *models.Order, capacity *clients.SlotCapacity, promo *models.PromoCode, sendToSyrve bool, risk *models.PhoneRisk, saved func(ctx context.Context, tx *sqlx.Tx, order *models.Order) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	return order, nil
+	txRepo := s.repository.WithTx(tx)
+	if err := s.lockSlot(ctx, txRepo, capacity, order); err != nil {
+		return err
+	}
+	if err := s.checkSlotCapacity(ctx, txRepo, capacity, order); err != nil {
+		return err
+	}
+
+	if err := txRepo.CreateOrder(ctx, order); err != nil {
+		return err
+	}
+
+	if err := s.orderItemRepository.WithTx(tx).CreateOrderItems(ctx, order.Items); err != nil {
+		return err
+	}
+
+	if promo != nil {
+		if err := s.promoService.RecordUsage(ctx, tx, promo, order.ID, order.Phone); err != nil {
+			return err
+		}
+	}
+
+	entry := newStatusHistory(order.ID, nil, order.StatusID, "customer", "order created")
+	if err := s.statusHistoryRepository.WithTx(tx).CreateStatusHistory(ctx, entry); err != nil {
+		return err
+	}
+
+	txOutbox := s.outboxRepository.WithTx(tx)
+	if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueTelegram, s.orderNotificationPayload(order, risk)); err != nil {
+		return err
+	}
+	if sendToSyrve {
+		if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueSyrve, order); err != nil {
+			return err
+		}
+	}
+
+	if saved != nil {
+		if err := saved(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, order *models.Order) error {
@@ -312,7 +416,16 @@ func (s *OrderService) CreateOrder(ctx context.Context, order *models.Order) err
 }
 
 func (s *OrderService) GetOrderById(ctx context.Context, id uuid.UUID) (*models.Order, error) {
-	return s.repository.GetOrderById(ctx, id)
+	order, err := s.repository.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachItemVariations(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
 }
 
 func (s *OrderService) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
@@ -353,11 +466,15 @@ func (s *OrderService) GetAllOrders(ctx context.Context) ([]*models.Order, error
 		}
 	}
 
+	if err := s.attachItemVariations(ctx, orders...); err != nil {
+		return nil, err
+	}
+
 	return orders, nil
 }
 
-func (s *OrderService) GetOrdersWithPagination(ctx context.Context, limit, offset int, orderBy, orderDir string) ([]*models.Order, int, error) {
-	rawOrders, totalCount, err := s.repository.GetOrdersWithPagination(ctx, limit, offset, orderBy, orderDir)
+func (s *OrderService) GetOrdersWithPagination(ctx context.Context, filter models.OrderFilter, limit, offset int, orderBy, orderDir string) ([]*models.Order, int, error) {
+	rawOrders, totalCount, err := s.repository.GetOrdersWithPagination(ctx, filter, limit, offset, orderBy, orderDir)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -394,14 +511,64 @@ func (s *OrderService) GetOrdersWithPagination(ctx context.Context, limit, offse
 		}
 	}
 
+	if err := s.attachItemVariations(ctx, orders...); err != nil {
+		return nil, 0, err
+	}
+
 	return orders, totalCount, nil
 }
 
-func (s *OrderService) UpdateOrder(ctx context.Context, order *models.Order) error {
+func (s *OrderService) UpdateOrder(ctx context.Context, order *models.Order, changedBy string) error {
+	current, err := s.repository.GetOrderById(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	// Only a new phone is normalized, old orders may keep a number the backfill couldn't parse
+	if order.Phone != current.Phone {
+		if order.Phone, err = normalizePhone(order.Phone); err != nil {
+			return err
+		}
+	}
+
+	if order.StatusID == "" {
+		order.StatusID = current.StatusID
+	}
+	statusChanged := order.StatusID != current.StatusID
+	if statusChanged {
+		if order.StatusID == models.StatusPaid {
+			return ErrStatusPaidViaPayment
+		}
+		if order.StatusID == models.StatusCancelled {
+			return ErrStatusCancelledViaCancel
+		}
+		if !current.StatusID.CanTransitionTo(order.StatusID, order.PaymentMethod) {
+			return fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, current.StatusID, order.StatusID)
+		}
+	}
+
 	now := time.Now()
 	order.UpdatedAt = now
 
-	var totalPrice float64
+	// The customer an order belongs to never changes
+	order.UserID = current.UserID
+
+	// The hold is managed by the scheduled release only
+	order.Scheduled = current.Scheduled
+	order.ReleaseAt = current.ReleaseAt
+	order.TrackingToken = current.TrackingToken
+	order.CourierStatus = current.CourierStatus
+	order.CourierUpdatedAt = current.CourierUpdatedAt
+	order.LoyaltyPoints = current.LoyaltyPoints
+
+	// Payment and pricing were decided at checkout, an edit must not lose the invoice to refund
+	// or the discount and delivery the customer was charged
+	order.InvoiceID = current.InvoiceID
+	order.SyrveNotified = current.SyrveNotified
+	order.DeliveryCost = current.DeliveryCost
+	order.DeliveryDoorPrice = current.DeliveryDoorPrice
+
+	var itemsTotal float64
 
 	for i := range order.Items {
 		item := &order.Items[i]
@@ -413,32 +580,84 @@ func (s *OrderService) UpdateOrder(ctx context.Context, order *models.Order) err
 
 		item.TotalPrice = item.Price * float64(item.Quantity)
 		item.TotalWeight = item.Weight * float64(item.Quantity)
+		item.Discount = math.Min(keptLineDiscount(current.Items, item), item.TotalPrice)
 
-		totalPrice += item.TotalPrice
+		itemsTotal += item.TotalPrice
 	}
 
-	order.TotalPrice = totalPrice
+	order.Discount = math.Min(current.Discount, itemsTotal)
+	order.TotalPrice = math.Max(orderTotal(itemsTotal, order.Discount, order.LoyaltyPoints, order.DeliveryCost, order.DeliveryDoorPrice), 0)
 
-	err := s.repository.UpdateOrder(ctx, order)
+	// The order, its items, the status history and the loyalty event change together
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	err = s.orderItemRepository.DeleteOrderItemsByOrderID(ctx, order.ID)
-	if err != nil {
+	if err := s.repository.WithTx(tx).UpdateOrder(ctx, order); err != nil {
 		return err
 	}
 
-	err = s.orderItemRepository.CreateOrderItems(ctx, order.Items)
-	if err != nil {
+	if statusChanged {
+		entry := newStatusHistory(order.ID, &current.StatusID, order.StatusID, changedBy, "order updated")
+		if err := s.statusHistoryRepository.WithTx(tx).CreateStatusHistory(ctx, entry); err != nil {
+			return err
+		}
+		// The order carries the customer and the charges of current by now
+		if err := enqueueLoyaltyEvent(ctx, s.outboxRepository.WithTx(tx), order); err != nil {
+			return err
+		}
+	}
+
+	txItems := s.orderItemRepository.WithTx(tx)
+	if err := txItems.DeleteOrderItemsByOrderID(ctx, order.ID); err != nil {
 		return err
 	}
+	if err := txItems.CreateOrderItems(ctx, order.Items); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	return nil
 }
 
+// keptLineDiscount is the discount the same product line had before the order was edited
+func keptLineDiscount(items []models.OrderItem, item *models.OrderItem) float64 {
+	for _, prev := range items {
+		if prev.ProductID != item.ProductID {
+			continue
+		}
+		if (prev.ProductVariationID == nil) != (item.ProductVariationID == nil) {
+			continue
+		}
+		if prev.ProductVariationID != nil && *prev.ProductVariationID != *item.ProductVariationID {
+			continue
+		}
+		return prev.Discount
+	}
+	return 0
+}
+
 func (s *OrderService) DeleteOrder(ctx context.Context, id uuid.UUID) error {
-	return s.repository.DeleteOrder(ctx, id)
+	order, err := s.repository.GetOrderById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteOrder(ctx, id); err != nil {
+		return err
+	}
+
+	// Stock of fulfilled orders is gone for real, everything else goes back
+	if order.StatusID != models.StatusCompleted && order.StatusID != models.StatusCancelled {
+		s.releaseStock(id)
+	}
+
+	return nil
 }
 
 func (s *OrderService) SetSyrveNotified(ctx context.Context, id uuid.UUID) (bool, error) {
@@ -448,14 +667,27 @@ func (s *OrderService) SetSyrveNotified(ctx context.Context, id uuid.UUID) (bool
 func (s *OrderService) getBasketOrders(order *models.Order) []monobank.BasketOrder {
 	var basket []monobank.BasketOrder
 
-	for _, item := range order.Items {
-		basket = append(basket, monobank.BasketOrder{
+	// Redeemed points are shown as a further discount of the item lines
+	loyalty := loyaltyShares(order.Items, order.LoyaltyPoints)
+
+	for i, item := range order.Items {
+		line := monobank.BasketOrder{
 			Name: item.Name,
 			Qty:  item.Quantity,
-			Sum:  int(item.Price * 100), // coins
-			Icon: "",                    // Add icon if available
+			Sum:  int(math.Round(item.Price * 100)), // coins, variation surcharges included
+			Icon: "",                                // Add icon if available
 			Code: item.ExternalProductID,
-		})
+		}
+
+		if discount := item.Discount + loyalty[i]; discount > 0 {
+			line.Discounts = []monobank.Discount{{
+				Type:  "DISCOUNT",
+				Mode:  "VALUE",
+				Value: strconv.Itoa(int(math.Round(discount * 100))), // coins
+			}}
+		}
+
+		basket = append(basket, line)
 	}
 
 	if order.DeliveryDoor {
@@ -477,13 +709,8 @@ func (s *OrderService) getBasketOrders(order *models.Order) []monobank.BasketOrd
 	return basket
 }
 
-func (s *OrderService) sendOrderNotification(order *models.Order) {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Println("Recovered in notification sender:", r)
-		}
-	}()
-
+// orderNotificationPayload is the Telegram message about a new order, with what we know about the customer's phone
+func (s *OrderService) orderNotificationPayload(order *models.Order, risk *models.PhoneRisk) map[string]interface{} {
 	var itemsList string
 	for _, item := range order.Items {
 		// Escape item name for HTML
@@ -565,21 +792,36 @@ func (s *OrderService) sendOrderNotification(order *models.Order) {
 		html.EscapeString(paymentStatus),
 	)
 
+	if order.Scheduled && order.ReleaseAt != nil {
+		msgText += fmt.Sprintf("\n\n⏳ <b>Передзамовлення:</b> на кухню о %s", order.ReleaseAt.In(s.location).Format("15:04 02.01.2006"))
+	}
+
 	if order.Wishes != "" {
 		msgText += fmt.Sprintf("\n\n💬 <b>Побажання:</b> %s", html.EscapeString(order.Wishes))
 	}
 
 	if order.Promo != "" {
 		msgText += fmt.Sprintf("\n\n🎟 <b>Промокод:</b> %s", html.EscapeString(order.Promo))
+		if order.Discount > 0 {
+			msgText += fmt.Sprintf(" (-%.0f ₴)", order.Discount)
+		}
+	}
+
+	if order.LoyaltyPoints > 0 {
+		msgText += fmt.Sprintf("\n\n⭐ <b>Оплачено бонусами:</b> %d ₴", order.LoyaltyPoints)
+	}
+
+	if risk != nil {
+		msgText += "\n\n" + phoneRiskText(risk)
 	}
 
 	// Construct Inline Keyboard safely
-	// For pickup orders, only show phone button
-	// For delivery orders, show: Map, Phone, Address, and Taxi
-	
+	// For pickup orders, only show phone and tracking link buttons
+	// For delivery orders, show: Map, Phone, Address, Tracking link and Taxi
+
 	var buttons []interface{}
 	isPickup := order.DeliveryTypeID == "pickup"
-	
+
 	// 1. Map (skip for pickup)
 	if !isPickup && mapLink != "" {
 		buttons = append(buttons, map[string]interface{}{
@@ -587,7 +829,7 @@ func (s *OrderService) sendOrderNotification(order *models.Order) {
 			"url":  mapLink,
 		})
 	}
-	
+
 	// 2. Phone Copy (always show)
 	if order.Phone != "" {
 		buttons = append(buttons, map[string]interface{}{
@@ -597,7 +839,7 @@ func (s *OrderService) sendOrderNotification(order *models.Order) {
 			},
 		})
 	}
-	
+
 	// 3. Address Copy (skip for pickup)
 	if !isPickup && order.Address != "" {
 		buttons = append(buttons, map[string]interface{}{
@@ -607,24 +849,58 @@ func (s *OrderService) sendOrderNotification(order *models.Order) {
 			},
 		})
 	}
-	
+
+	// 4. Tracking link for the customer
+	if order.TrackingToken != nil {
+		buttons = append(buttons, map[string]interface{}{
+			"text": "🔗",
+			"copy_text": map[string]string{
+				"text": trackingURL(*order.TrackingToken),
+			},
+		})
+	}
+
 	// Build keyboard rows
 	var keyboardRows []interface{}
 	if len(buttons) > 0 {
 		keyboardRows = append(keyboardRows, buttons)
 	}
-	
-	// 4. Taxi button (skip for pickup)
+
+	// 5. Taxi button (skip for pickup)
 	if !isPickup {
 		taxiButton := []interface{}{
 			map[string]interface{}{
-				"text": "🚕 Викликати таксі",
+				"text":          "🚕 Викликати таксі",
 				"callback_data": fmt.Sprintf("call_taxi:%s", order.ID),
 			},
 		}
 		keyboardRows = append(keyboardRows, taxiButton)
 	}
 
+	// 6. Early release of a held pre-order
+	if order.Scheduled {
+		releaseButton := []interface{}{
+			map[string]interface{}{
+				"text":          "🍳 Передати на кухню зараз",
+				"callback_data": fmt.Sprintf("release_order:%s", order.ID),
+			},
+		}
+		keyboardRows = append(keyboardRows, releaseButton)
+	}
+
+	// 7. Flag the customer's phone
+	flagButtons := []interface{}{
+		map[string]interface{}{
+			"text":          "🚩 Лише онлайн-оплата",
+			"callback_data": fmt.Sprintf("flag_phone:%s:%s", order.ID, models.RiskOnlineOnly),
+		},
+		map[string]interface{}{
+			"text":          "⛔ Заблокувати номер",
+			"callback_data": fmt.Sprintf("flag_phone:%s:%s", order.ID, models.RiskBlock),
+		},
+	}
+	keyboardRows = append(keyboardRows, flagButtons)
+
 	keyboard := map[string]interface{}{
 		"inline_keyboard": keyboardRows,
 	}
@@ -643,11 +919,11 @@ func (s *OrderService) sendOrderNotification(order *models.Order) {
 		"map_link":     mapLink,
 	}
 
-	jsonBody, _ := json.Marshal(payload)
-	_ = s.producer.SendMessage(rabbitmq.QueueTelegram, string(jsonBody))
+	return payload
 }
 
-func (s *OrderService) sendPaymentNotification(order *models.Order, invoiceID string) {
+// paymentNotificationPayload is the Telegram message about a paid order
+func (s *OrderService) paymentNotificationPayload(order *models.Order, invoiceID string) map[string]interface{} {
 	msgText := fmt.Sprintf(
 		"💸 Замовлення #%s сплачено\nIдентифікатор платежу: %s",
 		strings.ToUpper(order.ID.String()[:8]),
@@ -657,13 +933,10 @@ func (s *OrderService) sendPaymentNotification(order *models.Order, invoiceID st
 	chatIDStr := helpers.GetEnv("TELEGRAM_CHAT_ID", "0")
 	chatID, _ := strconv.ParseInt(chatIDStr, 10, 64)
 
-	payload := map[string]interface{}{
+	return map[string]interface{}{
 		"chat_id": chatID,
 		"text":    msgText,
 	}
-
-	jsonBody, _ := json.Marshal(payload)
-	_ = s.producer.SendMessage(rabbitmq.QueueTelegram, string(jsonBody))
 }
 
 type PaymentSuccessEvent struct {
@@ -681,26 +954,43 @@ func (s *OrderService) ProcessPaymentSuccess(event PaymentSuccessEvent) error {
 		return fmt.Errorf("failed to find order by invoice id %s: %w", event.InvoiceID, err)
 	}
 
+	if err := s.attachItemVariations(ctx, order); err != nil {
+		return err
+	}
+
 	// 2. Check status
 	if order.StatusID == models.StatusPaid {
 		// Already paid
 		return nil
 	}
+	if order.StatusID == models.StatusCancelled {
+		return s.refundLatePayment(ctx, order, event.InvoiceID)
+	}
 
-	// 3. Update status
-	order.StatusID = models.StatusPaid
-	// We might store 'payed' bool logic here if strictly following PHP but StatusPaid is better
+	// 3. Update status (only a pending order can become paid) together with the notification
+	// and the kitchen ticket, a redelivered event finds the order paid and must not lose them
+	now := time.Now()
+	err = s.transitionStatus(ctx, order, models.StatusPaid, StatusChangedByPayment, fmt.Sprintf("invoice %s", event.InvoiceID), func(tx *sqlx.Tx) error {
+		txOutbox := s.outboxRepository.WithTx(tx)
+		if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueTelegram, s.paymentNotificationPayload(order, event.InvoiceID)); err != nil {
+			return err
+		}
 
-	if err := s.repository.UpdateOrder(ctx, order); err != nil {
+		// Send to Syrve, a held pre-order goes once its release time comes
+		if !order.Scheduled {
+			return enqueueEvent(ctx, txOutbox, rabbitmq.QueueSyrve, order)
+		}
+		if order.ReleaseAt != nil && order.ReleaseAt.After(now) {
+			return nil
+		}
+		_, err := s.releaseOrderTx(ctx, tx, order, now, false)
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update order status: %w", err)
 	}
 
-	// 4. Send notification
-	go s.sendPaymentNotification(order, event.InvoiceID)
-
-	// 5. Send to Syrve
-	orderJSON, _ := json.Marshal(order)
-	_ = s.producer.SendMessage(rabbitmq.QueueSyrve, string(orderJSON))
+	s.commitStock(order.ID)
 
 	return nil
 }
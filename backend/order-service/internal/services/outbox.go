@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+	"github.com/tonysanin/brobar/pkg/rabbitmq"
+)
+
+const (
+	outboxBatchSize    = 50
+	outboxClaimLease   = 2 * time.Minute
+	outboxRetryBase    = 5 * time.Second
+	outboxRetryMax     = 10 * time.Minute
+	outboxSentEventTTL = 7 * 24 * time.Hour
+)
+
+// OutboxHandlerKey names events the relay delivers with a registered handler instead of
+// publishing them to RabbitMQ
+type OutboxHandlerKey string
+
+// OutboxHandler delivers the events written under its key
+type OutboxHandler func(ctx context.Context, payload string) error
+
+// enqueueEvent writes a message for the relay to publish. Pass a transactional
+// repository to make the event part of the surrounding change.
+func enqueueEvent(ctx context.Context, outbox *repositories.OutboxRepository, queue rabbitmq.QueueName, payload interface{}) error {
+	return createOutboxEvent(ctx, outbox, string(queue), payload, time.Time{})
+}
+
+// enqueueHandlerEvent writes an event for the handler registered under key, see enqueueEvent.
+// The event is delivered from deliverAt on, right away when it is zero.
+func enqueueHandlerEvent(ctx context.Context, outbox *repositories.OutboxRepository, key OutboxHandlerKey, payload interface{}, deliverAt time.Time) error {
+	return createOutboxEvent(ctx, outbox, string(key), payload, deliverAt)
+}
+
+func createOutboxEvent(ctx context.Context, outbox *repositories.OutboxRepository, destination string, payload interface{}, deliverAt time.Time) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", destination, err)
+	}
+
+	return outbox.CreateEvent(ctx, &models.OutboxEvent{
+		Queue:         destination,
+		Payload:       string(body),
+		NextAttemptAt: deliverAt,
+	})
+}
+
+// OutboxRelay publishes outbox events to RabbitMQ, retrying failed ones with backoff
+type OutboxRelay struct {
+	repository *repositories.OutboxRepository
+	producer   *rabbitmq.Producer
+	handlers   map[OutboxHandlerKey]OutboxHandler
+}
+
+func NewOutboxRelay(repository *repositories.OutboxRepository, producer *rabbitmq.Producer) *OutboxRelay {
+	return &OutboxRelay{repository: repository, producer: producer, handlers: make(map[OutboxHandlerKey]OutboxHandler)}
+}
+
+// Handle makes the relay deliver the events written under key with handler, with the same retries
+func (r *OutboxRelay) Handle(key OutboxHandlerKey, handler OutboxHandler) {
+	r.handlers[key] = handler
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, event models.OutboxEvent) error {
+	if handler, ok := r.handlers[OutboxHandlerKey(event.Queue)]; ok {
+		return handler(ctx, event.Payload)
+	}
+	return r.producer.SendMessage(rabbitmq.QueueName(event.Queue), event.Payload)
+}
+
+// Run relays due events every interval and drops old sent ones, until ctx is done
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := r.RelayPending(ctx, time.Now())
+				if err != nil {
+					log.Printf("failed to relay outbox events: %v", err)
+					break
+				}
+				if processed < outboxBatchSize {
+					break
+				}
+			}
+
+			if time.Since(lastCleanup) >= time.Hour {
+				lastCleanup = time.Now()
+				if _, err := r.repository.DeleteSentBefore(ctx, lastCleanup.Add(-outboxSentEventTTL)); err != nil {
+					log.Printf("failed to clean up outbox events: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// RelayPending publishes one batch of due events and returns how many were processed.
+// The batch is claimed first and delivered without holding a transaction, so a slow
+// handler doesn't keep rows locked. A failed publish is rescheduled instead of blocking
+// the rest of the batch.
+func (r *OutboxRelay) RelayPending(ctx context.Context, now time.Time) (int, error) {
+	events, err := r.repository.ClaimPendingEvents(ctx, now, now.Add(outboxClaimLease), outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := r.deliver(ctx, event); err != nil {
+			log.Printf("failed to publish outbox event %s to %s (attempt %d): %v", event.ID, event.Queue, event.Attempts+1, err)
+			if err := r.repository.MarkFailed(ctx, event.ID, err.Error(), time.Now().Add(outboxBackoff(event.Attempts))); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := r.repository.MarkSent(ctx, event.ID, time.Now()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// outboxBackoff doubles the delay with every failed attempt, up to outboxRetryMax
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 0; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMax {
+		delay = outboxRetryMax
+	}
+	return delay
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+var ErrInvalidPhone = errors.New("невірний номер телефону")
+
+// normalizePhone brings the phone to E.164 so every spelling of a number is stored the same
+func normalizePhone(phone string) (string, error) {
+	normalized, err := validator.NormalizePhone(phone)
+	if err != nil {
+		return "", ErrInvalidPhone
+	}
+	return normalized, nil
+}
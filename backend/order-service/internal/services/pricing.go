@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// PricingInput is the part of an order that determines its price
+type PricingInput struct {
+	Phone          string
+	DeliveryTypeID string
+	DeliveryDoor   bool
+	Coords         string
+	PromoCode      string
+	LoyaltyPoints  int
+	Items          []OrderItemInput
+}
+
+// OrderPricing is the server-side price breakdown of a cart
+type OrderPricing struct {
+	Items             []models.OrderItem
+	ItemsTotal        float64
+	Discount          float64
+	Promo             *models.PromoCode
+	LoyaltyPoints     int
+	DeliveryCost      float64
+	DeliveryDoorPrice float64
+	ZoneName          string
+	Zone              *clients.DeliveryZone
+	Total             float64
+}
+
+// priceOrder builds order items with actual prices, applies the promo code and
+// calculates delivery. Both the quote and order creation go through it.
+func (s *OrderService) priceOrder(ctx context.Context, input *PricingInput) (*OrderPricing, error) {
+	pricing := &OrderPricing{}
+	categories := make(map[uuid.UUID]uuid.UUID)
+
+	// 1. Fetch products and build order items with actual prices
+	productIDs := make([]uuid.UUID, len(input.Items))
+	for i, itemInput := range input.Items {
+		productIDs[i] = itemInput.ProductID
+	}
+
+	products, err := s.productClient.ResolveProducts(productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, itemInput := range input.Items {
+		product, ok := products[itemInput.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotFound, itemInput.ProductID.String())
+		}
+		categories[product.ID] = product.CategoryID
+
+		// Validation: Check stock
+		// If stock is nil, it means unlimited
+		if product.Stock != nil {
+			if float64(itemInput.Quantity) > *product.Stock {
+				return nil, fmt.Errorf("товар %s закінчився (доступно: %.0f, бажано: %d)", product.Name, *product.Stock, itemInput.Quantity)
+			}
+		}
+
+		item := models.OrderItem{
+			ID:                uuid.New(),
+			ProductID:         itemInput.ProductID,
+			ExternalProductID: product.ExternalID,
+			Quantity:          itemInput.Quantity,
+			Price:             product.Price,
+			Weight:            product.Weight,
+		}
+
+		selections := itemInput.Variations
+		if len(selections) == 0 && itemInput.ProductVariationID != nil {
+			selections = []VariationSelection{{VariationID: *itemInput.ProductVariationID, Quantity: 1}}
+		}
+
+		variations, err := selectVariations(&product, selections)
+		if err != nil {
+			return nil, err
+		}
+		applyVariations(&item, product.Name, variations)
+
+		item.TotalPrice = item.Price * float64(item.Quantity)
+		item.TotalWeight = item.Weight * float64(item.Quantity)
+
+		pricing.ItemsTotal += item.TotalPrice
+		pricing.Items = append(pricing.Items, item)
+	}
+
+	// 2. Apply promo code (discount is spread over the eligible items)
+	if strings.TrimSpace(input.PromoCode) != "" {
+		result, err := s.promoService.ApplyPromoCode(ctx, input.PromoCode, input.Phone, pricing.Items, categories, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		pricing.Discount = result.Discount
+		pricing.Promo = result.Promo
+	}
+
+	// 2.1 Pay part of the items with loyalty points
+	if input.LoyaltyPoints > 0 {
+		if err := s.applyLoyaltyPoints(pricing, input.LoyaltyPoints); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Calculate delivery cost by coordinates (free delivery threshold uses the discounted items total)
+	if input.DeliveryTypeID == "delivery" && input.Coords != "" {
+		cost, doorPrice, zone, err := s.validationService.GetDeliveryCost(input.Coords, input.DeliveryDoor, pricing.ItemsTotal-pricing.Discount)
+		if err != nil {
+			return nil, err
+		}
+		pricing.DeliveryCost = cost
+		pricing.DeliveryDoorPrice = doorPrice
+		if zone != nil {
+			pricing.ZoneName = zone.Name
+			pricing.Zone = zone
+		}
+	}
+
+	// 4. Calculate server total
+	pricing.Total = orderTotal(pricing.ItemsTotal, pricing.Discount, pricing.LoyaltyPoints, pricing.DeliveryCost, pricing.DeliveryDoorPrice)
+
+	return pricing, nil
+}
+
+// orderTotal is what the customer pays: the items less the discount and the redeemed points, plus delivery
+func orderTotal(itemsTotal, discount float64, loyaltyPoints int, deliveryCost, deliveryDoorPrice float64) float64 {
+	return itemsTotal - discount - float64(loyaltyPoints) + deliveryCost + deliveryDoorPrice
+}
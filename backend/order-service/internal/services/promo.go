@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+var (
+	ErrPromoNotFound      = errors.New("промокод не знайдено")
+	ErrPromoInactive      = errors.New("промокод недійсний")
+	ErrPromoMinCart       = errors.New("сума замовлення недостатня для промокоду")
+	ErrPromoUsageLimit    = errors.New("ліміт використань промокоду вичерпано")
+	ErrPromoNotApplicable = errors.New("промокод не діє на товари в кошику")
+)
+
+type PromoService struct {
+	repository *repositories.PromoRepository
+}
+
+func NewPromoService(repository *repositories.PromoRepository) *PromoService {
+	return &PromoService{repository: repository}
+}
+
+// PromoResult holds the code that was applied and the total discount it produced
+type PromoResult struct {
+	Promo    *models.PromoCode
+	Discount float64
+}
+
+// ApplyPromoCode validates the code against the cart and spreads the discount over eligible items.
+// Items must already have TotalPrice calculated; categories maps product ID to its category ID.
+func (s *PromoService) ApplyPromoCode(ctx context.Context, code, phone string, items []models.OrderItem, categories map[uuid.UUID]uuid.UUID, now time.Time) (*PromoResult, error) {
+	promo, err := s.repository.GetPromoCodeByCode(ctx, strings.TrimSpace(code))
+	if err != nil {
+		if errors.Is(err, customerrors.PromoCodeNotFound) {
+			return nil, ErrPromoNotFound
+		}
+		return nil, err
+	}
+
+	if !promo.Active {
+		return nil, ErrPromoInactive
+	}
+	if promo.StartsAt != nil && now.Before(*promo.StartsAt) {
+		return nil, ErrPromoInactive
+	}
+	if promo.EndsAt != nil && now.After(*promo.EndsAt) {
+		return nil, ErrPromoInactive
+	}
+
+	var cartTotal float64
+	for _, item := range items {
+		cartTotal += item.TotalPrice
+	}
+	if cartTotal < promo.MinCart {
+		return nil, fmt.Errorf("%w (мінімум: %.0f ₴)", ErrPromoMinCart, promo.MinCart)
+	}
+
+	if err := checkPromoUsages(ctx, s.repository, promo, phone); err != nil {
+		return nil, err
+	}
+
+	discount, err := discountItems(promo, items, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PromoResult{Promo: promo, Discount: discount}, nil
+}
+
+// discountItems sets the discount of the eligible items and returns its total
+func discountItems(promo *models.PromoCode, items []models.OrderItem, categories map[uuid.UUID]uuid.UUID) (float64, error) {
+	var eligible []int
+	var eligibleTotal float64
+	for i, item := range items {
+		if !isPromoEligible(promo, item.ProductID, categories[item.ProductID]) {
+			continue
+		}
+		eligible = append(eligible, i)
+		eligibleTotal += item.TotalPrice
+	}
+	if eligibleTotal <= 0 {
+		return 0, ErrPromoNotApplicable
+	}
+
+	var discount float64
+	switch promo.DiscountType {
+	case models.PromoDiscountPercent:
+		discount = eligibleTotal * promo.DiscountValue / 100
+	case models.PromoDiscountFixed:
+		discount = math.Min(promo.DiscountValue, eligibleTotal)
+	default:
+		return 0, ErrPromoInactive
+	}
+	discount = roundMoney(discount)
+
+	// Split the discount proportionally, the last line takes the rounding remainder
+	remaining := discount
+	for n, i := range eligible {
+		share := roundMoney(discount * items[i].TotalPrice / eligibleTotal)
+		if n == len(eligible)-1 {
+			share = roundMoney(remaining)
+		}
+		items[i].Discount = share
+		remaining -= share
+	}
+
+	return discount, nil
+}
+
+// checkPromoUsages rejects the code when its total or per-phone usage limit is reached
+func checkPromoUsages(ctx context.Context, repository *repositories.PromoRepository, promo *models.PromoCode, phone string) error {
+	if promo.MaxUses == nil && promo.MaxUsesPerPhone == nil {
+		return nil
+	}
+
+	total, byPhone, err := repository.CountUsages(ctx, promo.ID, phone)
+	if err != nil {
+		return err
+	}
+	if usageLimitReached(promo, total, byPhone) {
+		return ErrPromoUsageLimit
+	}
+	return nil
+}
+
+// usageLimitReached reports whether one more use would exceed the total or the per-phone limit
+func usageLimitReached(promo *models.PromoCode, total, byPhone int) bool {
+	if promo.MaxUses != nil && total >= *promo.MaxUses {
+		return true
+	}
+	return promo.MaxUsesPerPhone != nil && byPhone >= *promo.MaxUsesPerPhone
+}
+
+// RecordUsage counts the order against the limits of the code within the order's transaction.
+// The code row is locked first, so concurrent checkouts can't both take the last usage.
+func (s *PromoService) RecordUsage(ctx context.Context, tx *sqlx.Tx, promo *models.PromoCode, orderID uuid.UUID, phone string) error {
+	repository := s.repository.WithTx(tx)
+	if err := repository.LockPromoCode(ctx, promo.ID); err != nil {
+		if errors.Is(err, customerrors.PromoCodeNotFound) {
+			return ErrPromoNotFound
+		}
+		return err
+	}
+	if err := checkPromoUsages(ctx, repository, promo, phone); err != nil {
+		return err
+	}
+
+	return repository.CreateUsage(ctx, &models.PromoCodeUsage{
+		PromoCodeID: promo.ID,
+		OrderID:     orderID,
+		Phone:       phone,
+	})
+}
+
+// ReleaseUsage gives the usage of a cancelled order back to the code
+func (s *PromoService) ReleaseUsage(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error {
+	return s.repository.WithTx(tx).DeleteUsagesByOrderID(ctx, orderID)
+}
+
+func (s *PromoService) GetAllPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	return s.repository.GetAllPromoCodes(ctx)
+}
+
+func (s *PromoService) GetPromoCodeById(ctx context.Context, id uuid.UUID) (*models.PromoCode, error) {
+	return s.repository.GetPromoCodeById(ctx, id)
+}
+
+func (s *PromoService) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	if promo.ID == uuid.Nil {
+		promo.ID = uuid.New()
+	}
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+
+	now := time.Now()
+	promo.CreatedAt = now
+	promo.UpdatedAt = now
+
+	return s.repository.CreatePromoCode(ctx, promo)
+}
+
+func (s *PromoService) UpdatePromoCode(ctx context.Context, promo *models.PromoCode) error {
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+	promo.UpdatedAt = time.Now()
+
+	return s.repository.UpdatePromoCode(ctx, promo)
+}
+
+func (s *PromoService) DeletePromoCode(ctx context.Context, id uuid.UUID) error {
+	return s.repository.DeletePromoCode(ctx, id)
+}
+
+func isPromoEligible(promo *models.PromoCode, productID, categoryID uuid.UUID) bool {
+	if !promo.IsScoped() {
+		return true
+	}
+	for _, id := range promo.ProductIDs {
+		if id == productID.String() {
+			return true
+		}
+	}
+	if categoryID != uuid.Nil {
+		for _, id := range promo.CategoryIDs {
+			if id == categoryID.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestDiscountItems(t *testing.T) {
+	pizza, drink, dessert := uuid.New(), uuid.New(), uuid.New()
+	drinks := uuid.New()
+	categories := map[uuid.UUID]uuid.UUID{drink: drinks}
+
+	tests := []struct {
+		name      string
+		promo     models.PromoCode
+		totals    []float64
+		want      float64
+		discounts []float64
+		err       error
+	}{
+		{
+			name:      "percent split proportionally",
+			promo:     models.PromoCode{DiscountType: models.PromoDiscountPercent, DiscountValue: 10},
+			totals:    []float64{200, 100, 0},
+			want:      30,
+			discounts: []float64{20, 10, 0},
+		},
+		{
+			name:      "last line takes the rounding remainder",
+			promo:     models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10},
+			totals:    []float64{100, 100, 100},
+			want:      10,
+			discounts: []float64{3.33, 3.33, 3.34},
+		},
+		{
+			name:      "fixed discount capped at eligible total",
+			promo:     models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 500, ProductIDs: pq.StringArray{pizza.String()}},
+			totals:    []float64{120, 80, 50},
+			want:      120,
+			discounts: []float64{120, 0, 0},
+		},
+		{
+			name:      "scoped to a category",
+			promo:     models.PromoCode{DiscountType: models.PromoDiscountPercent, DiscountValue: 50, CategoryIDs: pq.StringArray{drinks.String()}},
+			totals:    []float64{200, 60, 40},
+			want:      30,
+			discounts: []float64{0, 30, 0},
+		},
+		{
+			name:   "nothing eligible",
+			promo:  models.PromoCode{DiscountType: models.PromoDiscountPercent, DiscountValue: 10, ProductIDs: pq.StringArray{uuid.NewString()}},
+			totals: []float64{200, 60, 40},
+			err:    ErrPromoNotApplicable,
+		},
+		{
+			name:   "unknown discount type",
+			promo:  models.PromoCode{DiscountType: "bogus", DiscountValue: 10},
+			totals: []float64{200, 60, 40},
+			err:    ErrPromoInactive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []models.OrderItem{
+				{ProductID: pizza, TotalPrice: tt.totals[0]},
+				{ProductID: drink, TotalPrice: tt.totals[1]},
+				{ProductID: dessert, TotalPrice: tt.totals[2]},
+			}
+
+			discount, err := discountItems(&tt.promo, items, categories)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, discount)
+			for i, want := range tt.discounts {
+				assert.InDelta(t, want, items[i].Discount, 0.001, "item %d", i)
+			}
+		})
+	}
+}
+
+func TestIsPromoEligible(t *testing.T) {
+	product, category := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		promo      models.PromoCode
+		categoryID uuid.UUID
+		want       bool
+	}{
+		{"unscoped code applies to everything", models.PromoCode{}, uuid.Nil, true},
+		{"listed product", models.PromoCode{ProductIDs: pq.StringArray{product.String()}}, uuid.Nil, true},
+		{"other product", models.PromoCode{ProductIDs: pq.StringArray{uuid.NewString()}}, category, false},
+		{"listed category", models.PromoCode{CategoryIDs: pq.StringArray{category.String()}}, category, true},
+		{"product without category", models.PromoCode{CategoryIDs: pq.StringArray{category.String()}}, uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPromoEligible(&tt.promo, product, tt.categoryID))
+		})
+	}
+}
+
+func TestUsageLimitReached(t *testing.T) {
+	tests := []struct {
+		name           string
+		promo          models.PromoCode
+		total, byPhone int
+		want           bool
+	}{
+		{"no limits", models.PromoCode{}, 100, 100, false},
+		{"below total limit", models.PromoCode{MaxUses: intPtr(10)}, 9, 0, false},
+		{"total limit reached", models.PromoCode{MaxUses: intPtr(10)}, 10, 0, true},
+		{"below phone limit", models.PromoCode{MaxUsesPerPhone: intPtr(2)}, 50, 1, false},
+		{"phone limit reached", models.PromoCode{MaxUsesPerPhone: intPtr(2)}, 50, 2, true},
+		{"either limit is enough", models.PromoCode{MaxUses: intPtr(10), MaxUsesPerPhone: intPtr(1)}, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usageLimitReached(&tt.promo, tt.total, tt.byPhone))
+		})
+	}
+}
+
+func TestRoundMoney(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{10, 10},
+		{3.333, 3.33},
+		{3.335, 3.34},
+		{0.004, 0},
+		{-1.006, -1.01},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, roundMoney(tt.in), "roundMoney(%v)", tt.in)
+	}
+}
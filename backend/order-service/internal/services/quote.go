@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+var (
+	ErrQuoteInvalid = errors.New("розрахунок замовлення недійсний, оновіть кошик")
+	ErrQuoteExpired = errors.New("розрахунок замовлення застарів, оновіть кошик")
+)
+
+// QuoteLine is one priced cart line of a quote
+type QuoteLine struct {
+	ProductID          uuid.UUID                   `json:"product_id"`
+	ProductVariationID *uuid.UUID                  `json:"product_variation_id,omitempty"`
+	Variations         []models.OrderItemVariation `json:"variations,omitempty"`
+	Name               string                      `json:"name"`
+	Price              float64                     `json:"price"`
+	Quantity           int                         `json:"quantity"`
+	TotalPrice         float64                     `json:"total_price"`
+	Discount           float64                     `json:"discount"`
+}
+
+// OrderQuote is the price breakdown returned to the checkout together with a signed token
+type OrderQuote struct {
+	Lines             []QuoteLine `json:"lines"`
+	ItemsTotal        float64     `json:"items_total"`
+	Discount          float64     `json:"discount"`
+	PromoCode         string      `json:"promo_code,omitempty"`
+	LoyaltyPoints     int         `json:"loyalty_points,omitempty"`
+	DeliveryCost      float64     `json:"delivery_cost"`
+	DeliveryDoorPrice float64     `json:"delivery_door_price"`
+	Zone              string      `json:"zone,omitempty"`
+	ZoneRules         *ZoneRules  `json:"zone_rules,omitempty"`
+	Total             float64     `json:"total"`
+	Token             string      `json:"token"`
+	ExpiresAt         time.Time   `json:"expires_at"`
+}
+
+// ZoneRules are the delivery zone restrictions the checkout has to respect
+type ZoneRules struct {
+	MinOrderAmount float64  `json:"min_order_amount,omitempty"`
+	PaymentMethods []string `json:"payment_methods,omitempty"`
+	PrepMinutes    int      `json:"prep_minutes,omitempty"`
+}
+
+// QuoteOrder prices the cart exactly like order creation does and signs the result
+func (s *OrderService) QuoteOrder(ctx context.Context, input *PricingInput) (*OrderQuote, error) {
+	if input.Phone != "" {
+		phone, err := normalizePhone(input.Phone)
+		if err != nil {
+			return nil, err
+		}
+		input.Phone = phone
+	}
+
+	pricing, err := s.priceOrder(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	token, expiresAt, err := s.quoteSigner.Sign(input, pricing.Total, now)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &OrderQuote{
+		Lines:             make([]QuoteLine, len(pricing.Items)),
+		ItemsTotal:        pricing.ItemsTotal,
+		Discount:          pricing.Discount,
+		LoyaltyPoints:     pricing.LoyaltyPoints,
+		DeliveryCost:      pricing.DeliveryCost,
+		DeliveryDoorPrice: pricing.DeliveryDoorPrice,
+		Zone:              pricing.ZoneName,
+		Total:             pricing.Total,
+		Token:             token,
+		ExpiresAt:         expiresAt,
+	}
+	if pricing.Promo != nil {
+		quote.PromoCode = pricing.Promo.Code
+	}
+	if zone := pricing.Zone; zone != nil {
+		quote.ZoneRules = &ZoneRules{
+			MinOrderAmount: zone.MinOrderAmount,
+			PaymentMethods: zone.PaymentMethods,
+			PrepMinutes:    zone.PrepMinutes,
+		}
+	}
+	for i, item := range pricing.Items {
+		quote.Lines[i] = QuoteLine{
+			ProductID:          item.ProductID,
+			ProductVariationID: item.ProductVariationID,
+			Variations:         item.Variations,
+			Name:               item.Name,
+			Price:              item.Price,
+			Quantity:           item.Quantity,
+			TotalPrice:         item.TotalPrice,
+			Discount:           item.Discount,
+		}
+	}
+
+	return quote, nil
+}
+
+// QuoteSigner issues and checks HMAC-signed quote tokens bound to a cart
+type QuoteSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewQuoteSigner(secret string, ttl time.Duration) *QuoteSigner {
+	return &QuoteSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// QuoteClaims is the signed part of a quote token
+type QuoteClaims struct {
+	CartHash  string  `json:"cart"`
+	Total     float64 `json:"total"`
+	ExpiresAt int64   `json:"exp"`
+}
+
+// Sign returns a token of the form base64(claims).base64(hmac)
+func (q *QuoteSigner) Sign(input *PricingInput, total float64, now time.Time) (string, time.Time, error) {
+	cartHash, err := hashPricingInput(input)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := now.Add(q.ttl)
+	payload, err := json.Marshal(QuoteClaims{
+		CartHash:  cartHash,
+		Total:     total,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to marshal quote: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + q.signature(encoded), expiresAt, nil
+}
+
+// Verify checks the signature, expiry and that the token was issued for this cart
+func (q *QuoteSigner) Verify(token string, input *PricingInput, now time.Time) (*QuoteClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(q.signature(encoded))) {
+		return nil, ErrQuoteInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrQuoteInvalid
+	}
+
+	var claims QuoteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrQuoteInvalid
+	}
+
+	if now.Unix() > claims.ExpiresAt {
+		return nil, ErrQuoteExpired
+	}
+
+	cartHash, err := hashPricingInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if cartHash != claims.CartHash {
+		return nil, ErrQuoteInvalid
+	}
+
+	return &claims, nil
+}
+
+func (q *QuoteSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, q.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashPricingInput fingerprints everything that affects the price, so a token can't be reused for another cart
+func hashPricingInput(input *PricingInput) (string, error) {
+	cart := struct {
+		DeliveryTypeID string
+		DeliveryDoor   bool
+		Coords         string
+		PromoCode      string
+		LoyaltyPoints  int
+		Items          []OrderItemInput
+	}{
+		DeliveryTypeID: input.DeliveryTypeID,
+		DeliveryDoor:   input.DeliveryDoor,
+		Coords:         strings.TrimSpace(input.Coords),
+		PromoCode:      strings.ToUpper(strings.TrimSpace(input.PromoCode)),
+		LoyaltyPoints:  input.LoyaltyPoints,
+		Items:          input.Items,
+	}
+
+	data, err := json.Marshal(cart)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cart: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteSignerVerify(t *testing.T) {
+	signer := NewQuoteSigner("secret", 15*time.Minute)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := uuid.New()
+
+	cart := func() *PricingInput {
+		return &PricingInput{
+			DeliveryTypeID: "delivery",
+			Coords:         "50.0,36.2",
+			PromoCode:      "spring",
+			Items:          []OrderItemInput{{ProductID: product, Quantity: 2}},
+		}
+	}
+
+	token, expiresAt, err := signer.Sign(cart(), 420, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name   string
+		signer *QuoteSigner
+		token  string
+		input  func() *PricingInput
+		now    time.Time
+		err    error
+	}{
+		{
+			name:   "valid",
+			signer: signer,
+			token:  token,
+			input:  cart,
+			now:    now.Add(14 * time.Minute),
+		},
+		{
+			name:   "promo code case and spaces don't matter",
+			signer: signer,
+			token:  token,
+			input: func() *PricingInput {
+				input := cart()
+				input.PromoCode = " SPRING "
+				return input
+			},
+			now: now,
+		},
+		{
+			name:   "expired",
+			signer: signer,
+			token:  token,
+			input:  cart,
+			now:    now.Add(16 * time.Minute),
+			err:    ErrQuoteExpired,
+		},
+		{
+			name:   "other cart",
+			signer: signer,
+			token:  token,
+			input: func() *PricingInput {
+				input := cart()
+				input.Items[0].Quantity = 3
+				return input
+			},
+			now: now,
+			err: ErrQuoteInvalid,
+		},
+		{
+			name:   "other secret",
+			signer: NewQuoteSigner("other", 15*time.Minute),
+			token:  token,
+			input:  cart,
+			now:    now,
+			err:    ErrQuoteInvalid,
+		},
+		{
+			name:   "tampered claims",
+			signer: signer,
+			token:  encoded + "x." + signature,
+			input:  cart,
+			now:    now,
+			err:    ErrQuoteInvalid,
+		},
+		{
+			name:   "no signature",
+			signer: signer,
+			token:  encoded,
+			input:  cart,
+			now:    now,
+			err:    ErrQuoteInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.signer.Verify(tt.token, tt.input(), tt.now)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 420.0, claims.Total)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+var (
+	ErrPhoneBlocked    = errors.New("замовлення з цього номера не приймаються, зателефонуйте нам")
+	ErrPhoneOnlineOnly = errors.New("для цього номера доступна лише онлайн-оплата")
+)
+
+// phoneKeyDigits is the subscriber part of a Ukrainian number, the same with or without +380 or 0
+const phoneKeyDigits = 9
+
+// RiskService keeps the registry of risky customer phones
+type RiskService struct {
+	repository      *repositories.PhoneFlagRepository
+	orderRepository *repositories.OrderRepository
+}
+
+func NewRiskService(repository *repositories.PhoneFlagRepository, orderRepository *repositories.OrderRepository) *RiskService {
+	return &RiskService{repository: repository, orderRepository: orderRepository}
+}
+
+// phoneKey reduces a phone to its last digits so different spellings of a number match
+func phoneKey(phone string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+
+	if len(digits) < phoneKeyDigits {
+		return "", ErrInvalidPhone
+	}
+	return digits[len(digits)-phoneKeyDigits:], nil
+}
+
+func (s *RiskService) GetAllFlags(ctx context.Context) ([]models.PhoneFlag, error) {
+	return s.repository.GetAllFlags(ctx)
+}
+
+// GetPhoneRisk returns the flag of the phone, if any, and its order history without the excluded order
+func (s *RiskService) GetPhoneRisk(ctx context.Context, phone string, excludeOrderID uuid.UUID) (*models.PhoneRisk, error) {
+	key, err := phoneKey(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	risk := &models.PhoneRisk{}
+
+	flag, err := s.repository.GetFlag(ctx, key)
+	if err != nil && !errors.Is(err, customerrors.PhoneFlagNotFound) {
+		return nil, err
+	}
+	risk.Flag = flag
+
+	history, err := s.orderRepository.GetPhoneHistory(ctx, key, excludeOrderID)
+	if err != nil {
+		return nil, err
+	}
+	risk.History = *history
+
+	return risk, nil
+}
+
+// checkPhoneRisk rejects orders from blocked phones and cash orders from phones that must pay online
+func checkPhoneRisk(risk *models.PhoneRisk, paymentMethod string) error {
+	if risk.Flag == nil {
+		return nil
+	}
+
+	switch risk.Flag.Action {
+	case models.RiskBlock:
+		return ErrPhoneBlocked
+	case models.RiskOnlineOnly:
+		if paymentMethod != "online" {
+			return ErrPhoneOnlineOnly
+		}
+	}
+	return nil
+}
+
+// FlagPhone flags the phone, replacing its previous flag
+func (s *RiskService) FlagPhone(ctx context.Context, flag *models.PhoneFlag) error {
+	phone, err := normalizePhone(flag.Phone)
+	if err != nil {
+		return err
+	}
+	key, err := phoneKey(phone)
+	if err != nil {
+		return err
+	}
+	flag.Phone = phone
+	flag.PhoneKey = key
+
+	return s.repository.UpsertFlag(ctx, flag)
+}
+
+func (s *RiskService) UnflagPhone(ctx context.Context, phone string) error {
+	key, err := phoneKey(phone)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.DeleteFlag(ctx, key)
+}
+
+// phoneRiskText is the customer history line of the order notification
+func phoneRiskText(risk *models.PhoneRisk) string {
+	var sb strings.Builder
+	if risk.History.Orders == 0 {
+		sb.WriteString("📊 <b>Новий клієнт</b>")
+	} else {
+		sb.WriteString(fmt.Sprintf(
+			"📊 <b>Історія:</b> замовлень %d, виконано %d, не забрали %d",
+			risk.History.Orders, risk.History.Completed, risk.History.NoShows,
+		))
+	}
+
+	if flag := risk.Flag; flag != nil {
+		label := "🚩 Лише онлайн-оплата"
+		if flag.Action == models.RiskBlock {
+			label = "⛔ Номер заблоковано"
+		}
+		sb.WriteString("\n<b>" + label + "</b>")
+		if flag.Reason != nil && *flag.Reason != "" {
+			sb.WriteString(": " + html.EscapeString(*flag.Reason))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/helpers"
+	"github.com/tonysanin/brobar/pkg/rabbitmq"
+)
+
+var ErrOrderNotScheduled = errors.New("замовлення не очікує на передачу на кухню")
+
+// holdIfScheduled marks a pre-order to be held until the kitchen has to start on it,
+// the prep lead time of its delivery type plus the zone's extra time before the requested time
+func (s *OrderService) holdIfScheduled(order *models.Order, now time.Time, extra time.Duration) {
+	releaseAt := order.Time.Add(-s.prepLeadTimes[order.DeliveryTypeID] - extra)
+	if !releaseAt.After(now) {
+		return
+	}
+
+	order.Scheduled = true
+	order.ReleaseAt = &releaseAt
+}
+
+// readyForKitchen reports whether the order may go to Syrve, online orders wait for the payment
+func readyForKitchen(order *models.Order) bool {
+	if order.StatusID == models.StatusCancelled {
+		return false
+	}
+	return order.PaymentMethod != "online" || order.StatusID != models.StatusPending
+}
+
+// RunScheduledRelease sends held pre-orders to the kitchen every interval until ctx is done
+func (s *OrderService) RunScheduledRelease(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ReleaseDueOrders(ctx, time.Now()); err != nil {
+				log.Printf("failed to release scheduled orders: %v", err)
+			}
+		}
+	}
+}
+
+// ReleaseDueOrders sends held orders whose release time has come to Syrve. Returns the released orders.
+func (s *OrderService) ReleaseDueOrders(ctx context.Context, now time.Time) ([]models.Order, error) {
+	due, err := s.repository.GetDueScheduledOrders(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var released []models.Order
+	for _, dueOrder := range due {
+		order, err := s.GetOrderById(ctx, dueOrder.ID)
+		if err != nil {
+			log.Printf("failed to load scheduled order %s: %v", dueOrder.ID, err)
+			continue
+		}
+
+		ok, err := s.releaseOrder(ctx, order, now, false)
+		if err != nil {
+			log.Printf("failed to release scheduled order %s: %v", order.ID, err)
+			continue
+		}
+		if ok {
+			released = append(released, *order)
+		}
+	}
+
+	return released, nil
+}
+
+// ReleaseScheduledOrder sends a held order to the kitchen right away. An unpaid online
+// order is only unheld and goes to the kitchen as soon as it is paid.
+func (s *OrderService) ReleaseScheduledOrder(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	order, err := s.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.Scheduled || order.StatusID == models.StatusCancelled {
+		return nil, ErrOrderNotScheduled
+	}
+
+	released, err := s.releaseOrder(ctx, order, time.Now(), true)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return nil, ErrOrderNotScheduled
+	}
+
+	return order, nil
+}
+
+// releaseOrder takes the hold off the order and enqueues it for Syrve together with a Telegram
+// notice. It returns false when the order was already released.
+func (s *OrderService) releaseOrder(ctx context.Context, order *models.Order, now time.Time, early bool) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	released, err := s.releaseOrderTx(ctx, tx, order, now, early)
+	if err != nil || !released {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// releaseOrderTx is releaseOrder within the caller's transaction
+func (s *OrderService) releaseOrderTx(ctx context.Context, tx *sqlx.Tx, order *models.Order, now time.Time, early bool) (bool, error) {
+	released, err := s.repository.WithTx(tx).ReleaseScheduled(ctx, order.ID, now)
+	if err != nil {
+		return false, err
+	}
+	if !released {
+		return false, nil
+	}
+
+	order.Scheduled = false
+	order.ReleaseAt = &now
+
+	txOutbox := s.outboxRepository.WithTx(tx)
+	if readyForKitchen(order) {
+		if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueSyrve, order); err != nil {
+			return false, err
+		}
+	}
+	if err := enqueueEvent(ctx, txOutbox, rabbitmq.QueueTelegram, s.releaseNotificationPayload(order, early)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// releaseNotificationPayload is the Telegram message about a pre-order sent to the kitchen
+func (s *OrderService) releaseNotificationPayload(order *models.Order, early bool) map[string]interface{} {
+	msgText := fmt.Sprintf(
+		"🍳 Передзамовлення #%s передано на кухню (на %s)",
+		strings.ToUpper(order.ID.String()[:8]),
+		order.Time.In(s.location).Format("15:04 02.01.2006"),
+	)
+	if early {
+		msgText += "\nПередано достроково"
+	}
+	if !readyForKitchen(order) {
+		msgText = fmt.Sprintf(
+			"🍳 Передзамовлення #%s буде передано на кухню одразу після оплати",
+			strings.ToUpper(order.ID.String()[:8]),
+		)
+	}
+
+	chatIDStr := helpers.GetEnv("TELEGRAM_CHAT_ID", "0")
+	chatID, _ := strconv.ParseInt(chatIDStr, 10, 64)
+
+	return map[string]interface{}{
+		"chat_id": chatID,
+		"text":    msgText,
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/order-service/internal/repositories"
+)
+
+var ErrSlotFull = errors.New("на обраний час вже немає вільних місць, оберіть інший час")
+
+// GetSlotCapacity returns the time slot limits
+func (s *ValidationService) GetSlotCapacity() (*clients.SlotCapacity, error) {
+	capacity, err := s.webClient.GetSlotCapacity()
+	if err != nil {
+		return nil, fmt.Errorf("не вдалося отримати ліміти часу: %w", err)
+	}
+	return capacity, nil
+}
+
+// shiftFor returns the shift starting on the date, with date overrides applied
+func (s *ValidationService) shiftFor(date time.Time, deliveryType string) (workingShift, error) {
+	workingHours, err := s.webClient.GetWorkingHours()
+	if err != nil {
+		return workingShift{}, fmt.Errorf("не вдалося отримати розклад: %w", err)
+	}
+
+	return shiftOn(workingHours, date, deliveryType)
+}
+
+// slotStart returns the beginning of the slot containing t, slots are counted from local midnight
+func slotStart(t time.Time, loc *time.Location, slotMinutes int) time.Time {
+	local := t.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	minutes -= minutes % slotMinutes
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, minutes, 0, 0, loc)
+}
+
+// checkSlotCapacity rejects the order when its time slot has no room left for it
+func (s *OrderService) checkSlotCapacity(ctx context.Context, repository *repositories.OrderRepository, capacity *clients.SlotCapacity, order *models.Order) error {
+	limit := capacity.Limit(string(order.DeliveryTypeID))
+	if limit.Unlimited() {
+		return nil
+	}
+
+	start := slotStart(order.Time, s.location, capacity.SlotMinutes)
+	taken, err := repository.GetSlotOrders(ctx, order.DeliveryTypeID, start, start.Add(time.Duration(capacity.SlotMinutes)*time.Minute))
+	if err != nil {
+		return err
+	}
+	if slotFull(limit, taken, order) {
+		return ErrSlotFull
+	}
+
+	return nil
+}
+
+// slotFull reports whether the order doesn't fit next to the orders already taken in its slot
+func slotFull(limit clients.SlotLimit, taken []models.SlotOrder, order *models.Order) bool {
+	items := 0
+	for _, item := range order.Items {
+		items += item.Quantity
+	}
+	for _, slotOrder := range taken {
+		items += slotOrder.Items
+	}
+
+	if limit.MaxOrders > 0 && len(taken)+1 > limit.MaxOrders {
+		return true
+	}
+	return limit.MaxItems > 0 && items > limit.MaxItems
+}
+
+// lockSlot makes concurrent orders for the same slot wait for each other, so the
+// capacity check inside the transaction sees every committed order
+func (s *OrderService) lockSlot(ctx context.Context, repository *repositories.OrderRepository, capacity *clients.SlotCapacity, order *models.Order) error {
+	if capacity.Limit(string(order.DeliveryTypeID)).Unlimited() {
+		return nil
+	}
+
+	start := slotStart(order.Time, s.location, capacity.SlotMinutes)
+	return repository.LockSlot(ctx, fmt.Sprintf("order_slot:%s:%s", order.DeliveryTypeID, start.Format(time.RFC3339)))
+}
+
+// GetAvailableSlots lists the order times of the shift starting on the date, marking the slots
+// that are already full. Times of a shift running past midnight carry the next date. Past
+// times are left out.
+func (s *OrderService) GetAvailableSlots(ctx context.Context, date string, deliveryType models.DeliveryType) (*models.DaySlots, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, s.location)
+	if err != nil {
+		return nil, fmt.Errorf("невірний формат дати")
+	}
+
+	result := &models.DaySlots{Date: date, Slots: []models.TimeSlot{}}
+
+	shift, err := s.validationService.shiftFor(day, string(deliveryType))
+	if err != nil {
+		return nil, err
+	}
+	if shift.Closed {
+		result.Closed = true
+		result.Reason = shift.Reason
+		return result, nil
+	}
+	result.Reason = shift.Reason
+
+	capacity, err := s.validationService.GetSlotCapacity()
+	if err != nil {
+		return nil, err
+	}
+	step := time.Duration(capacity.SlotMinutes) * time.Minute
+	limit := capacity.Limit(string(deliveryType))
+
+	taken, err := s.repository.GetSlotOrders(ctx, deliveryType, slotStart(shift.Start, s.location, capacity.SlotMinutes), shift.End.Add(step))
+	if err != nil {
+		return nil, err
+	}
+
+	type usage struct{ orders, items int }
+	used := make(map[time.Time]usage)
+	for _, slotOrder := range taken {
+		start := slotStart(slotOrder.Time, s.location, capacity.SlotMinutes)
+		u := used[start]
+		u.orders++
+		u.items += slotOrder.Items
+		used[start] = u
+	}
+
+	now := time.Now()
+	for t := shift.Start; !t.After(shift.End); t = t.Add(step) {
+		if !t.After(now) {
+			continue
+		}
+
+		u := used[slotStart(t, s.location, capacity.SlotMinutes)]
+		available := (limit.MaxOrders <= 0 || u.orders < limit.MaxOrders) &&
+			(limit.MaxItems <= 0 || u.items < limit.MaxItems)
+
+		local := t.In(s.location)
+		result.Slots = append(result.Slots, models.TimeSlot{
+			Date:      local.Format("2006-01-02"),
+			Time:      local.Format("15:04"),
+			Available: available,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+func TestSlotStart(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		t           time.Time
+		slotMinutes int
+		want        time.Time
+	}{
+		{"start of a slot", time.Date(2026, 3, 10, 14, 30, 0, 0, kyiv), 30, time.Date(2026, 3, 10, 14, 30, 0, 0, kyiv)},
+		{"inside a slot", time.Date(2026, 3, 10, 14, 44, 59, 0, kyiv), 30, time.Date(2026, 3, 10, 14, 30, 0, 0, kyiv)},
+		{"hour slots", time.Date(2026, 3, 10, 9, 59, 0, 0, kyiv), 60, time.Date(2026, 3, 10, 9, 0, 0, 0, kyiv)},
+		{"slots not dividing an hour", time.Date(2026, 3, 10, 1, 10, 0, 0, kyiv), 45, time.Date(2026, 3, 10, 0, 45, 0, 0, kyiv)},
+		{"converted to local time", time.Date(2026, 3, 10, 22, 20, 0, 0, time.UTC), 30, time.Date(2026, 3, 11, 0, 0, 0, 0, kyiv)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(slotStart(tt.t, kyiv, tt.slotMinutes)), "got %v", slotStart(tt.t, kyiv, tt.slotMinutes))
+		})
+	}
+}
+
+func TestSlotFull(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)
+	order := &models.Order{Items: []models.OrderItem{{Quantity: 2}, {Quantity: 1}}}
+	taken := []models.SlotOrder{{Time: at, Items: 4}, {Time: at, Items: 2}}
+
+	tests := []struct {
+		name  string
+		limit clients.SlotLimit
+		taken []models.SlotOrder
+		want  bool
+	}{
+		{"unlimited", clients.SlotLimit{}, taken, false},
+		{"room for one more order", clients.SlotLimit{MaxOrders: 3}, taken, false},
+		{"orders limit reached", clients.SlotLimit{MaxOrders: 2}, taken, true},
+		{"items fit exactly", clients.SlotLimit{MaxItems: 9}, taken, false},
+		{"too many items", clients.SlotLimit{MaxItems: 8}, taken, true},
+		{"order alone over the items limit", clients.SlotLimit{MaxItems: 2}, nil, true},
+		{"empty slot", clients.SlotLimit{MaxOrders: 1, MaxItems: 3}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, slotFull(tt.limit, tt.taken, order))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+var (
+	ErrInvalidStatusTransition = errors.New("неможливо змінити статус замовлення")
+	ErrStatusPaidViaPayment    = errors.New("статус \"оплачено\" встановлюється лише після оплати")
+	ErrStatusConflict          = errors.New("статус замовлення вже було змінено")
+)
+
+// StatusChangedByPayment marks history entries written by the payment flow
+const StatusChangedByPayment = "payment"
+
+// ChangeOrderStatus applies a manual status change, rejecting transitions the table does not allow.
+// Cancelling goes through CancelOrder.
+func (s *OrderService) ChangeOrderStatus(ctx context.Context, id uuid.UUID, to models.Status, changedBy, reason string) (*models.Order, error) {
+	if to == models.StatusPaid {
+		return nil, ErrStatusPaidViaPayment
+	}
+	if to == models.StatusCancelled {
+		return s.CancelOrder(ctx, id, changedBy, reason, false)
+	}
+
+	order, err := s.repository.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transitionStatus(ctx, order, to, changedBy, reason, nil); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) GetOrderStatusHistory(ctx context.Context, id uuid.UUID) ([]models.OrderStatusHistory, error) {
+	if _, err := s.repository.GetOrderById(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.statusHistoryRepository.GetStatusHistoryByOrderID(ctx, id)
+}
+
+// transitionStatus moves the order to the given status and records the change in history.
+// then writes the events of the change, it sees the order in its new status and runs in the
+// same transaction, so the status never changes without them. then may be nil.
+func (s *OrderService) transitionStatus(ctx context.Context, order *models.Order, to models.Status, changedBy, reason string, then func(tx *sqlx.Tx) error) error {
+	from := order.StatusID
+	if !from.CanTransitionTo(to, order.PaymentMethod) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updated, err := s.repository.WithTx(tx).UpdateOrderStatus(ctx, order.ID, from, to)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrStatusConflict
+	}
+
+	entry := newStatusHistory(order.ID, &from, to, changedBy, reason)
+	if err := s.statusHistoryRepository.WithTx(tx).CreateStatusHistory(ctx, entry); err != nil {
+		return err
+	}
+
+	// The order keeps its old status unless the change is committed
+	order.StatusID = to
+	committed := false
+	defer func() {
+		if !committed {
+			order.StatusID = from
+		}
+	}()
+
+	// A cancelled order gives its promo code usage back
+	if to == models.StatusCancelled {
+		if err := s.promoService.ReleaseUsage(ctx, tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if err := enqueueLoyaltyEvent(ctx, s.outboxRepository.WithTx(tx), order); err != nil {
+		return err
+	}
+	if then != nil {
+		if err := then(tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	if to == models.StatusCancelled {
+		s.releaseStock(order.ID)
+	}
+
+	return nil
+}
+
+func newStatusHistory(orderID uuid.UUID, from *models.Status, to models.Status, changedBy, reason string) *models.OrderStatusHistory {
+	entry := &models.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+	}
+	if reason != "" {
+		entry.Reason = &reason
+	}
+	return entry
+}
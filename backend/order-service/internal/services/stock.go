@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// reserveStock holds the order quantities in product-service until the order is confirmed or cancelled
+func (s *OrderService) reserveStock(order *models.Order) error {
+	items := make([]clients.StockItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = clients.StockItem{
+			ProductID: item.ProductID,
+			Quantity:  float64(item.Quantity),
+		}
+	}
+
+	if err := s.productClient.ReserveStock(order.ID, items); err != nil {
+		if errors.Is(err, clients.ErrInsufficientStock) {
+			return fmt.Errorf("%w (%v)", ErrOutOfStock, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *OrderService) commitStock(orderID uuid.UUID) {
+	if err := s.productClient.CommitStock(orderID); err != nil {
+		log.Printf("failed to commit stock for order %s: %v", orderID, err)
+	}
+}
+
+func (s *OrderService) releaseStock(orderID uuid.UUID) {
+	if err := s.productClient.ReleaseStock(orderID); err != nil {
+		log.Printf("failed to release stock for order %s: %v", orderID, err)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+	"github.com/tonysanin/brobar/pkg/helpers"
+)
+
+// newTrackingToken returns the secret that authorizes the customer tracking page of an order
+func newTrackingToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate tracking token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// trackingURL is the customer page of the order, also used as the payment redirect
+func trackingURL(token string) string {
+	return fmt.Sprintf("https://%s/order/success?token=%s", helpers.GetEnv("NGINX_DOMAIN", "brobar.delivery"), token)
+}
+
+// GetOrderTracking returns the customer view of the order the token belongs to
+func (s *OrderService) GetOrderTracking(ctx context.Context, token string) (*models.OrderTracking, error) {
+	id, err := s.repository.GetOrderIDByTrackingToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.statusHistoryRepository.GetStatusHistoryByOrderID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tracking := &models.OrderTracking{
+		Number:            strings.ToUpper(order.ID.String()[:8]),
+		Status:            order.StatusID,
+		DeliveryType:      order.DeliveryTypeID,
+		Time:              order.Time,
+		ETA:               s.trackingETA(order),
+		Scheduled:         order.Scheduled,
+		AcceptedByKitchen: order.SyrveNotified,
+		Timeline:          make([]models.TrackingEvent, len(history)),
+		Items:             make([]models.TrackingItem, len(order.Items)),
+		DeliveryCost:      order.DeliveryCost,
+		Discount:          order.Discount,
+		LoyaltyPoints:     order.LoyaltyPoints,
+		TotalPrice:        order.TotalPrice,
+	}
+
+	for i, entry := range history {
+		tracking.Timeline[i] = models.TrackingEvent{Status: entry.ToStatus, At: entry.CreatedAt}
+	}
+
+	for i, item := range order.Items {
+		trackingItem := models.TrackingItem{
+			Name:       item.Name,
+			Quantity:   item.Quantity,
+			TotalPrice: item.TotalPrice,
+		}
+		for _, variation := range item.Variations {
+			trackingItem.Variations = append(trackingItem.Variations, variation.Name)
+		}
+		if len(item.Variations) == 0 && item.ProductVariationName != nil {
+			trackingItem.Variations = []string{*item.ProductVariationName}
+		}
+		tracking.Items[i] = trackingItem
+	}
+
+	if order.CourierStatus != nil && order.CourierUpdatedAt != nil {
+		tracking.Courier = &models.TrackingCourier{
+			Status:    *order.CourierStatus,
+			UpdatedAt: *order.CourierUpdatedAt,
+		}
+	}
+
+	return tracking, nil
+}
+
+// trackingETA is when the order is expected to be delivered or ready: the requested time,
+// but not earlier than the kitchen lead time after the order was placed. Finished orders have none.
+func (s *OrderService) trackingETA(order *models.Order) *time.Time {
+	if order.StatusID == models.StatusCompleted || order.StatusID == models.StatusCancelled {
+		return nil
+	}
+
+	eta := order.Time
+	if earliest := order.CreatedAt.Add(s.prepLeadTimes[order.DeliveryTypeID]); eta.Before(earliest) {
+		eta = earliest
+	}
+	return &eta
+}
+
+// SetCourierStatus records the delivery state reported by ontaxi-service
+func (s *OrderService) SetCourierStatus(ctx context.Context, id uuid.UUID, status string) error {
+	return s.repository.SetCourierStatus(ctx, id, status, time.Now())
+}
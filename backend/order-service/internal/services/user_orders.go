@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	customerrors "github.com/tonysanin/brobar/order-service/internal/errors"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// GetUserOrders lists the customer's orders with their items, newest first
+func (s *OrderService) GetUserOrders(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Order, int, error) {
+	return s.GetOrdersWithPagination(ctx, models.OrderFilter{UserID: &userID}, limit, offset, "created_at", "desc")
+}
+
+// GetUserOrder returns the order only to the customer who placed it, other orders are not found
+func (s *OrderService) GetUserOrder(ctx context.Context, userID, id uuid.UUID) (*models.Order, error) {
+	order, err := s.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID == nil || *order.UserID != userID {
+		return nil, customerrors.OrderNotFound
+	}
+
+	return order, nil
+}
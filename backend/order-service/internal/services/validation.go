@@ -13,6 +13,14 @@ var (
 	ErrTimeNotAvailable = errors.New("обраний час недоступний")
 	ErrPriceMismatch    = errors.New("ціни змінились, будь ласка, перевірте замовлення")
 	ErrProductNotFound  = errors.New("товар не знайдено")
+	ErrSalesPaused      = errors.New("прийом замовлень тимчасово призупинено")
+	ErrOutOfStock       = errors.New("товару немає в наявності")
+
+	ErrBelowZoneMinimum       = errors.New("сума замовлення менша за мінімальну для вашої зони доставки")
+	ErrPaymentMethodNotInZone = errors.New("цей спосіб оплати недоступний для вашої зони доставки")
+
+	ErrVariationNotFound = errors.New("варіація не знайдена")
+	ErrVariationRequired = errors.New("оберіть обов'язкову варіацію")
 )
 
 type ValidationService struct {
@@ -37,99 +45,96 @@ var daysMap = map[int]string{
 	6: "saturday",
 }
 
-// ValidateOrderTime validates that the requested order time is within working hours
-func (s *ValidationService) ValidateOrderTime(timeStr string, deliveryType string) error {
-	serverTime, err := s.webClient.GetServerTime()
+// ValidateSalesOpen rejects orders while the "sales_paused" setting is on.
+// A pause with a resume time that has already passed is treated as lifted.
+func (s *ValidationService) ValidateSalesOpen(loc *time.Location) error {
+	pause, err := s.webClient.GetSalesPause(loc)
 	if err != nil {
-		return fmt.Errorf("не вдалося перевірити час: %w", err)
+		return fmt.Errorf("не вдалося перевірити статус продажів: %w", err)
 	}
 
-	workingHours, err := s.webClient.GetWorkingHours()
-	if err != nil {
-		return fmt.Errorf("не вдалося отримати розклад: %w", err)
+	if !pause.Paused {
+		return nil
 	}
-
-	// Handle ASAP
-	if strings.ToUpper(timeStr) == "ASAP" {
-		dayName := daysMap[serverTime.DayNumber]
-		currentTime := serverTime.Time[:5] // "HH:MM"
-
-		var schedule clients.DaySchedule
-		var ok bool
-
-		if deliveryType == "delivery" {
-			schedule, ok = workingHours.Delivery[dayName]
-		} else {
-			schedule, ok = workingHours.Pickup[dayName]
-		}
-
-		if !ok || schedule.Closed {
-			return ErrTimeNotAvailable
-		}
-
-		if currentTime < schedule.Start || currentTime >= schedule.End {
-			return ErrTimeNotAvailable
-		}
-
+	if pause.ResumeAt != nil && !time.Now().Before(*pause.ResumeAt) {
 		return nil
 	}
 
-	// Parse scheduled time "2026-01-18 14:30"
-	parts := strings.Split(timeStr, " ")
-	if len(parts) != 2 {
-		return fmt.Errorf("невірний формат часу")
+	msg := ""
+	if pause.Reason != "" {
+		msg = pause.Reason
+	}
+	if pause.ResumeAt != nil {
+		if msg != "" {
+			msg += ", "
+		}
+		msg += fmt.Sprintf("відновлення о %s", pause.ResumeAt.In(loc).Format("15:04 02.01"))
+	}
+	if msg != "" {
+		return fmt.Errorf("%w: %s", ErrSalesPaused, msg)
 	}
 
-	datePart := parts[0]
-	timePart := parts[1]
+	return ErrSalesPaused
+}
 
-	orderDate, err := time.Parse("2006-01-02", datePart)
+// ValidateOrderTime validates that the requested order time is within working hours,
+// taking date overrides and shifts past midnight into account
+func (s *ValidationService) ValidateOrderTime(timeStr string, deliveryType string, loc *time.Location) error {
+	serverTime, err := s.webClient.GetServerTime()
 	if err != nil {
-		return fmt.Errorf("невірний формат дати")
+		return fmt.Errorf("не вдалося перевірити час: %w", err)
 	}
 
-	serverDate, err := time.Parse("2006-01-02", serverTime.Date)
+	workingHours, err := s.webClient.GetWorkingHours()
 	if err != nil {
-		return fmt.Errorf("помилка серверного часу")
-	}
-
-	// Check if order date is in the past
-	if orderDate.Before(serverDate) {
-		return ErrTimeNotAvailable
+		return fmt.Errorf("не вдалося отримати розклад: %w", err)
 	}
 
-	// Get schedule for order day
-	dayName := daysMap[int(orderDate.Weekday())]
-	var schedule clients.DaySchedule
-	var ok bool
+	now := time.Unix(serverTime.Timestamp, 0).In(loc)
 
-	if deliveryType == "delivery" {
-		schedule, ok = workingHours.Delivery[dayName]
-	} else {
-		schedule, ok = workingHours.Pickup[dayName]
-	}
+	// Handle ASAP
+	asap := strings.ToUpper(timeStr) == "ASAP"
+	orderTime := now
+	if !asap {
+		// Parse scheduled time "2026-01-18 14:30"
+		orderTime, err = time.ParseInLocation("2006-01-02 15:04", timeStr, loc)
+		if err != nil {
+			return fmt.Errorf("невірний формат часу")
+		}
 
-	if !ok || schedule.Closed {
-		return ErrTimeNotAvailable
+		// Check that the time is not in the past
+		if !orderTime.After(now) {
+			return ErrTimeNotAvailable
+		}
 	}
 
-	// Check if time is within schedule
-	if timePart < schedule.Start || timePart > schedule.End {
-		return ErrTimeNotAvailable
+	open, reason, err := openAt(workingHours, orderTime, deliveryType, !asap)
+	if err != nil {
+		return err
 	}
-
-	// If order is for today, check that time is not in the past
-	if orderDate.Equal(serverDate) {
-		currentTime := serverTime.Time[:5]
-		if timePart <= currentTime {
-			return ErrTimeNotAvailable
+	if !open {
+		if reason != "" {
+			return fmt.Errorf("%w: %s", ErrTimeNotAvailable, reason)
 		}
+		return ErrTimeNotAvailable
 	}
 
 	return nil
 }
 
-// GetDeliveryCost calculates delivery cost based on coordinates, door delivery, and cart total
+// daySchedule picks the working hours of a weekday, pickup hours also apply to dine-in
+func daySchedule(workingHours *clients.WorkingHours, weekday int, deliveryType string) (clients.DaySchedule, bool) {
+	dayName := daysMap[weekday]
+	if deliveryType == "delivery" {
+		schedule, ok := workingHours.Delivery[dayName]
+		return schedule, ok
+	}
+	schedule, ok := workingHours.Pickup[dayName]
+	return schedule, ok
+}
+
+// GetDeliveryCost calculates delivery cost based on coordinates, door delivery, and cart total,
+// rejecting carts below the zone minimum
 // returns: total_cost, door_part_cost, zone, error
 func (s *ValidationService) GetDeliveryCost(coords string, deliveryDoor bool, cartTotal float64) (float64, float64, *clients.DeliveryZone, error) {
 	var zonePrice float64 = 0
@@ -150,6 +155,10 @@ func (s *ValidationService) GetDeliveryCost(coords string, deliveryDoor bool, ca
 			return 0, 0, nil, fmt.Errorf("адреса поза зоною доставки")
 		}
 
+		if zone.MinOrderAmount > 0 && cartTotal < zone.MinOrderAmount {
+			return 0, 0, nil, fmt.Errorf("%w (%s: від %.0f ₴)", ErrBelowZoneMinimum, zone.Name, zone.MinOrderAmount)
+		}
+
 		zonePrice = zone.Price
 
 		// Free delivery if cart total >= freeOrderPrice
@@ -159,8 +168,9 @@ func (s *ValidationService) GetDeliveryCost(coords string, deliveryDoor bool, ca
 	}
 
 	if deliveryDoor {
-		doorPrice, err := s.webClient.GetDeliveryDoorPrice()
-		if err == nil {
+		if zone != nil && zone.DoorPrice != nil {
+			doorPartPrice = *zone.DoorPrice
+		} else if doorPrice, err := s.webClient.GetDeliveryDoorPrice(); err == nil {
 			doorPartPrice = doorPrice
 		}
 	}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+	"github.com/tonysanin/brobar/order-service/internal/models"
+)
+
+// VariationSelection is a variation picked for a cart line
+type VariationSelection struct {
+	VariationID uuid.UUID
+	Quantity    int
+}
+
+// selectVariations checks the selections against the product's variation groups.
+// Groups left empty get their default variations (DefaultValue > 0 is the default quantity);
+// a required group that is still empty is an error.
+func selectVariations(product *clients.Product, selections []VariationSelection) ([]models.OrderItemVariation, error) {
+	type groupedVariation struct {
+		group     clients.VariationGroup
+		variation clients.Variation
+	}
+	index := make(map[uuid.UUID]groupedVariation)
+	for _, group := range product.VariationGroups {
+		for _, variation := range group.Variations {
+			index[variation.ID] = groupedVariation{group: group, variation: variation}
+		}
+	}
+
+	quantities := make(map[uuid.UUID]int)
+	var order []uuid.UUID
+	for _, selection := range selections {
+		if _, ok := index[selection.VariationID]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrVariationNotFound, selection.VariationID.String())
+		}
+		if _, ok := quantities[selection.VariationID]; !ok {
+			order = append(order, selection.VariationID)
+		}
+		quantities[selection.VariationID] += selection.Quantity
+	}
+
+	var result []models.OrderItemVariation
+	for _, group := range product.VariationGroups {
+		var selected []models.OrderItemVariation
+		for _, id := range order {
+			entry := index[id]
+			if entry.group.ID == group.ID {
+				selected = append(selected, newOrderItemVariation(group, entry.variation, quantities[id]))
+			}
+		}
+
+		if len(selected) == 0 {
+			for _, variation := range group.Variations {
+				if variation.DefaultValue != nil && *variation.DefaultValue > 0 {
+					selected = append(selected, newOrderItemVariation(group, variation, *variation.DefaultValue))
+				}
+			}
+		}
+
+		if len(selected) == 0 && group.Required {
+			return nil, fmt.Errorf("%w: %s — %s", ErrVariationRequired, product.Name, group.Name)
+		}
+
+		result = append(result, selected...)
+	}
+
+	return result, nil
+}
+
+func newOrderItemVariation(group clients.VariationGroup, variation clients.Variation, quantity int) models.OrderItemVariation {
+	return models.OrderItemVariation{
+		ID:          uuid.New(),
+		GroupID:     group.ID,
+		GroupName:   group.Name,
+		VariationID: variation.ID,
+		ExternalID:  variation.ExternalID,
+		Name:        variation.Name,
+		Quantity:    quantity,
+		Price:       variation.Price,
+	}
+}
+
+// applyVariations stores the selections on the item, adds their surcharges to the unit price
+// and fills the legacy single-variation fields with the first one, for clients that still read them
+func applyVariations(item *models.OrderItem, productName string, variations []models.OrderItemVariation) {
+	item.Variations = variations
+	item.Name = productName
+	for _, variation := range variations {
+		item.VariationsPrice += variation.Price * float64(variation.Quantity)
+	}
+	item.Price += item.VariationsPrice
+	if len(variations) == 0 {
+		return
+	}
+
+	first := variations[0]
+	item.ProductVariationGroupID = &first.GroupID
+	item.ProductVariationGroupName = &first.GroupName
+	item.ProductVariationID = &first.VariationID
+	item.ProductVariationExternalID = &first.ExternalID
+	item.ProductVariationName = &first.Name
+
+	names := make([]string, len(variations))
+	for i, variation := range variations {
+		names[i] = variation.Name
+		if variation.Quantity > 1 {
+			names[i] = fmt.Sprintf("%s ×%d", variation.Name, variation.Quantity)
+		}
+	}
+	item.Name = fmt.Sprintf("%s (%s)", productName, strings.Join(names, ", "))
+}
+
+// attachItemVariations loads the variation selections of the orders' items
+func (s *OrderService) attachItemVariations(ctx context.Context, orders ...*models.Order) error {
+	var itemIDs []uuid.UUID
+	for _, order := range orders {
+		for _, item := range order.Items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+	}
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	variations, err := s.orderItemRepository.GetVariationsByOrderItemIDs(ctx, itemIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order item variations: %w", err)
+	}
+
+	byItem := make(map[uuid.UUID][]models.OrderItemVariation)
+	for _, variation := range variations {
+		byItem[variation.OrderItemID] = append(byItem[variation.OrderItemID], variation)
+	}
+	for _, order := range orders {
+		for i := range order.Items {
+			order.Items[i].Variations = byItem[order.Items[i].ID]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+)
+
+// workingShift is the working interval that starts on a date, End is on the next day
+// for shifts running past midnight
+type workingShift struct {
+	Start  time.Time
+	End    time.Time
+	Closed bool
+	Reason string
+}
+
+// shiftOn returns the shift starting on the date (midnight in its location), a date
+// override takes precedence over the weekday hours
+func shiftOn(workingHours *clients.WorkingHours, date time.Time, deliveryType string) (workingShift, error) {
+	schedule, ok := daySchedule(workingHours, int(date.Weekday()), deliveryType)
+
+	var reason string
+	if override, found := workingHours.Override(date.Format("2006-01-02")); found {
+		schedule, ok, reason = override.DaySchedule, true, override.Reason
+	}
+
+	if !ok || schedule.Closed {
+		return workingShift{Closed: true, Reason: reason}, nil
+	}
+
+	start, err := atClock(date, schedule.Start)
+	if err != nil {
+		return workingShift{}, err
+	}
+	end, err := atClock(date, schedule.End)
+	if err != nil {
+		return workingShift{}, err
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return workingShift{Start: start, End: end, Reason: reason}, nil
+}
+
+// openAt reports whether t falls into the shift of its day or into the part of the previous
+// day's shift that runs past midnight. The end is included for scheduled times only, an ASAP
+// order needs the kitchen to still be open. When closed, the reason of the day's override is returned.
+func openAt(workingHours *clients.WorkingHours, t time.Time, deliveryType string, includeEnd bool) (bool, string, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	today, err := shiftOn(workingHours, day, deliveryType)
+	if err != nil {
+		return false, "", err
+	}
+	yesterday, err := shiftOn(workingHours, day.AddDate(0, 0, -1), deliveryType)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, shift := range []workingShift{yesterday, today} {
+		if !shift.Closed && shift.contains(t, includeEnd) {
+			return true, "", nil
+		}
+	}
+
+	return false, today.Reason, nil
+}
+
+func (s workingShift) contains(t time.Time, includeEnd bool) bool {
+	if t.Before(s.Start) {
+		return false
+	}
+	if includeEnd {
+		return !t.After(s.End)
+	}
+	return t.Before(s.End)
+}
+
+// atClock returns the "HH:MM" time on the date
+func atClock(date time.Time, clock string) (time.Time, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("невірний формат розкладу: %s", clock)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, date.Location()), nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonysanin/brobar/order-service/internal/clients"
+)
+
+func TestShiftOn(t *testing.T) {
+	loc := time.FixedZone("EET", 2*60*60)
+	// 2026-03-10 is a Tuesday
+	tuesday := time.Date(2026, 3, 10, 0, 0, 0, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, loc)
+	}
+
+	workingHours := &clients.WorkingHours{
+		Delivery: map[string]clients.DaySchedule{
+			"monday":    {Start: "10:00", End: "22:00"},
+			"tuesday":   {Start: "18:00", End: "02:00"},
+			"wednesday": {Start: "10:00", End: "10:00"},
+			"thursday":  {Closed: true},
+		},
+		Pickup: map[string]clients.DaySchedule{
+			"tuesday": {Start: "09:00", End: "21:00"},
+		},
+		Overrides: []clients.ScheduleOverride{
+			{Date: "2026-03-13", DaySchedule: clients.DaySchedule{Start: "20:00", End: "04:30"}, Reason: "party"},
+			{Date: "2026-03-14", DaySchedule: clients.DaySchedule{Closed: true}, Reason: "holiday"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		date         time.Time
+		deliveryType string
+		want         workingShift
+	}{
+		{"same day shift", tuesday.AddDate(0, 0, -1), "delivery", workingShift{Start: at(9, 10, 0), End: at(9, 22, 0)}},
+		{"shift past midnight", tuesday, "delivery", workingShift{Start: at(10, 18, 0), End: at(11, 2, 0)}},
+		{"round the clock", tuesday.AddDate(0, 0, 1), "delivery", workingShift{Start: at(11, 10, 0), End: at(12, 10, 0)}},
+		{"closed weekday", tuesday.AddDate(0, 0, 2), "delivery", workingShift{Closed: true}},
+		{"override past midnight", tuesday.AddDate(0, 0, 3), "delivery", workingShift{Start: at(13, 20, 0), End: at(14, 4, 30), Reason: "party"}},
+		{"closed override", tuesday.AddDate(0, 0, 4), "pickup", workingShift{Closed: true, Reason: "holiday"}},
+		{"no schedule for the day", tuesday.AddDate(0, 0, 5), "delivery", workingShift{Closed: true}},
+		{"pickup hours", tuesday, "pickup", workingShift{Start: at(10, 9, 0), End: at(10, 21, 0)}},
+		{"dine-in uses pickup hours", tuesday, "dine", workingShift{Start: at(10, 9, 0), End: at(10, 21, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift, err := shiftOn(workingHours, tt.date, tt.deliveryType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, shift)
+		})
+	}
+}
+
+func TestOpenAtPastMidnight(t *testing.T) {
+	loc := time.FixedZone("EET", 2*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, loc)
+	}
+
+	workingHours := &clients.WorkingHours{
+		Delivery: map[string]clients.DaySchedule{
+			"tuesday":   {Start: "18:00", End: "02:00"},
+			"wednesday": {Closed: true},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		includeEnd bool
+		want       bool
+	}{
+		{"before the shift", at(10, 17, 59), false, false},
+		{"evening", at(10, 23, 0), false, true},
+		{"after midnight on a closed day", at(11, 1, 30), false, true},
+		{"scheduled at the end", at(11, 2, 0), true, true},
+		{"asap at the end", at(11, 2, 0), false, false},
+		{"after the shift", at(11, 2, 1), true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, _, err := openAt(workingHours, tt.t, "delivery", tt.includeEnd)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, open)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+CREATE TABLE promo_codes (
+                             id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                             code VARCHAR(64) NOT NULL UNIQUE,
+                             discount_type VARCHAR(16) NOT NULL,
+                             discount_value NUMERIC(10,2) NOT NULL,
+                             min_cart NUMERIC(10,2) NOT NULL DEFAULT 0,
+                             starts_at TIMESTAMP WITH TIME ZONE,
+                             ends_at TIMESTAMP WITH TIME ZONE,
+                             max_uses INTEGER,
+                             max_uses_per_phone INTEGER,
+                             product_ids TEXT[] NOT NULL DEFAULT '{}',
+                             category_ids TEXT[] NOT NULL DEFAULT '{}',
+                             active BOOLEAN NOT NULL DEFAULT TRUE,
+                             created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+                             updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE promo_code_usages (
+                                   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                   promo_code_id UUID NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+                                   order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                                   phone VARCHAR(32) NOT NULL,
+                                   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_promo_code_usages_promo_code_id ON promo_code_usages(promo_code_id);
+CREATE INDEX idx_promo_code_usages_phone ON promo_code_usages(promo_code_id, phone);
+
+ALTER TABLE orders ADD COLUMN discount NUMERIC(10,2) DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN discount NUMERIC(10,2) DEFAULT 0;
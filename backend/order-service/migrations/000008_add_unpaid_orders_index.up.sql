@@ -0,0 +1 @@
+CREATE INDEX idx_orders_unpaid_online ON orders(created_at) WHERE status_id = 'pending' AND payment_method = 'online';
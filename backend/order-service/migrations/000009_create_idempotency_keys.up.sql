@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+                                  key VARCHAR(255) PRIMARY KEY,
+                                  request_hash CHAR(64) NOT NULL,
+                                  order_id UUID REFERENCES orders(id) ON DELETE CASCADE,
+                                  response JSONB,
+                                  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
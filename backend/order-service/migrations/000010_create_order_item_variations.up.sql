@@ -0,0 +1,12 @@
+CREATE TABLE order_item_variations (
+                                       id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                       order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+                                       group_id UUID NOT NULL,
+                                       group_name VARCHAR(255) NOT NULL,
+                                       variation_id UUID NOT NULL,
+                                       external_id VARCHAR(100) NOT NULL DEFAULT '',
+                                       name VARCHAR(255) NOT NULL,
+                                       quantity INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_order_item_variations_order_item_id ON order_item_variations(order_item_id);
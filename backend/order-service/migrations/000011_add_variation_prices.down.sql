@@ -0,0 +1,2 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS variations_price;
+ALTER TABLE order_item_variations DROP COLUMN IF EXISTS price;
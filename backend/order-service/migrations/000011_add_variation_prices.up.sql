@@ -0,0 +1,2 @@
+ALTER TABLE order_item_variations ADD COLUMN price NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN variations_price NUMERIC(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_orders_name_trgm;
+DROP INDEX IF EXISTS idx_orders_phone_trgm;
+DROP INDEX IF EXISTS idx_orders_total_price;
+DROP INDEX IF EXISTS idx_orders_delivery_type_created_at;
+DROP INDEX IF EXISTS idx_orders_status_created_at;
+DROP INDEX IF EXISTS idx_orders_time;
+DROP INDEX IF EXISTS idx_orders_created_at;
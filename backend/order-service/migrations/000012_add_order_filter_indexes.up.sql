@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_orders_created_at ON orders(created_at);
+CREATE INDEX idx_orders_time ON orders(time);
+CREATE INDEX idx_orders_status_created_at ON orders(status_id, created_at);
+CREATE INDEX idx_orders_delivery_type_created_at ON orders(delivery_type_id, created_at);
+CREATE INDEX idx_orders_total_price ON orders(total_price);
+CREATE INDEX idx_orders_phone_trgm ON orders USING gin (phone gin_trgm_ops);
+CREATE INDEX idx_orders_name_trgm ON orders USING gin (name gin_trgm_ops);
@@ -0,0 +1,13 @@
+CREATE TABLE outbox_events (
+                               id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                               queue VARCHAR(100) NOT NULL,
+                               payload TEXT NOT NULL,
+                               attempts INTEGER NOT NULL DEFAULT 0,
+                               last_error TEXT,
+                               created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+                               next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+                               sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE sent_at IS NULL;
+CREATE INDEX idx_outbox_events_sent_at ON outbox_events(sent_at) WHERE sent_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_scheduled_release_at;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS release_at,
+    DROP COLUMN IF EXISTS scheduled;
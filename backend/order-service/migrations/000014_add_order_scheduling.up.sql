@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN scheduled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN release_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_orders_scheduled_release_at ON orders(release_at) WHERE scheduled;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_tracking_token;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS courier_updated_at,
+    DROP COLUMN IF EXISTS courier_status,
+    DROP COLUMN IF EXISTS tracking_token;
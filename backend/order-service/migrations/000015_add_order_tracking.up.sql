@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    ADD COLUMN tracking_token VARCHAR(64),
+    ADD COLUMN courier_status VARCHAR(32),
+    ADD COLUMN courier_updated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX idx_orders_tracking_token ON orders(tracking_token);
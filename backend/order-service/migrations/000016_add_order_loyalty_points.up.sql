@@ -0,0 +1,2 @@
+ALTER TABLE orders
+    ADD COLUMN loyalty_points INTEGER NOT NULL DEFAULT 0;
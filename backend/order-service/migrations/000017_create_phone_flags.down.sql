@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_phone_key;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS no_show;
+
+DROP TABLE IF EXISTS phone_flags;
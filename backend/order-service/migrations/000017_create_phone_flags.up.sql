@@ -0,0 +1,16 @@
+-- Risk flags of customer phones, keyed by the last 9 digits of the number
+CREATE TABLE phone_flags (
+    phone_key VARCHAR(32) PRIMARY KEY,
+    phone VARCHAR(32) NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('online_only', 'block')),
+    reason TEXT,
+    flagged_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE orders
+    ADD COLUMN no_show BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Past orders of a phone are looked up by the same key
+CREATE INDEX idx_orders_phone_key ON orders (RIGHT(regexp_replace(phone, '[^0-9]', '', 'g'), 9));
@@ -0,0 +1,2 @@
+ALTER TABLE orders
+    ADD COLUMN refund_status VARCHAR(20);
@@ -61,6 +61,8 @@ func NewServer(cfg *config.Config) *Server {
 	api := s.app.Group("/api/v1")
 	payments := api.Group("/payments")
 	payments.Post("/init", paymentHandler.InitPayment)
+	payments.Post("/:invoice_id/cancel", paymentHandler.CancelPayment)
+	payments.Post("/:invoice_id/refund", paymentHandler.RefundPayment)
 
 	webhooks := s.app.Group("/webhooks")
 	webhooks.Post("/monobank", paymentHandler.HandleMonobankWebhook)
@@ -29,6 +29,43 @@ func (h *PaymentHandler) InitPayment(c fiber.Ctx) error {
 	return c.JSON(output)
 }
 
+func (h *PaymentHandler) CancelPayment(c fiber.Ctx) error {
+	invoiceID := c.Params("invoice_id")
+	if invoiceID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invoice id is required"})
+	}
+
+	if err := h.service.CancelPayment(invoiceID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *PaymentHandler) RefundPayment(c fiber.Ctx) error {
+	invoiceID := c.Params("invoice_id")
+	if invoiceID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invoice id is required"})
+	}
+
+	var input services.RefundPaymentInput
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	if input.Amount < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Amount must not be negative"})
+	}
+
+	output, err := h.service.RefundPayment(invoiceID, input)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(output)
+}
+
 func (h *PaymentHandler) HandleMonobankWebhook(c fiber.Ctx) error {
 	var payload services.WebhookPayload
 	// Monobank sends JSON
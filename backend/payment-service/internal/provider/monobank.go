@@ -5,7 +5,9 @@ import (
 )
 
 type PaymentProvider interface {
-	CreateInvoice(amount int, orderID string, redirectURL string, webhookURL string, basket []monobank.BasketOrder) (*monobank.InvoiceData, error)
+	CreateInvoice(amount int, orderID string, redirectURL string, webhookURL string, validity int, basket []monobank.BasketOrder) (*monobank.InvoiceData, error)
+	CancelInvoice(invoiceID string) error
+	RefundInvoice(invoiceID string, amount int) (string, error)
 }
 
 type MonobankProvider struct {
@@ -18,7 +20,7 @@ func NewMonobankProvider(client *monobank.Acquiring) *MonobankProvider {
 	}
 }
 
-func (m *MonobankProvider) CreateInvoice(amount int, orderID string, redirectURL string, webhookURL string, basket []monobank.BasketOrder) (*monobank.InvoiceData, error) {
+func (m *MonobankProvider) CreateInvoice(amount int, orderID string, redirectURL string, webhookURL string, validity int, basket []monobank.BasketOrder) (*monobank.InvoiceData, error) {
 	return m.client.CreateInvoice(&monobank.Invoice{
 		Amount: amount,
 		Ccy:    monobank.UAH,
@@ -29,5 +31,27 @@ func (m *MonobankProvider) CreateInvoice(amount int, orderID string, redirectURL
 		},
 		RedirectURL: redirectURL,
 		WebHookURL:  webhookURL,
+		Validity:    validity,
 	})
 }
+
+func (m *MonobankProvider) CancelInvoice(invoiceID string) error {
+	return m.client.RemoveInvoice(invoiceID)
+}
+
+// RefundInvoice returns the money of a paid invoice, amount 0 refunds it fully.
+// Returns the refund status reported by the bank. An invoice that was already
+// refunded is reported as "reversed" rather than as an error.
+func (m *MonobankProvider) RefundInvoice(invoiceID string, amount int) (string, error) {
+	resp, err := m.client.CancelInvoice(&monobank.CancelInvoiceRequest{
+		InvoiceID: invoiceID,
+		Amount:    amount,
+	})
+	if err != nil {
+		if status, statusErr := m.client.InvoiceStatus(invoiceID); statusErr == nil && status.Status == monobank.InvoiceStatusReversed {
+			return monobank.InvoiceStatusReversed, nil
+		}
+		return "", err
+	}
+	return resp.Status, nil
+}
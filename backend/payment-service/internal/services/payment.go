@@ -25,6 +25,7 @@ type InitPaymentInput struct {
 	OrderID     string                 `json:"order_id"`
 	RedirectURL string                 `json:"redirect_url"`
 	WebhookURL  string                 `json:"webhook_url"`
+	Validity    int                    `json:"validity,omitempty"` // seconds, provider default when empty
 	Basket      []monobank.BasketOrder `json:"basket"`
 }
 
@@ -39,6 +40,7 @@ func (s *PaymentService) InitPayment(input InitPaymentInput) (*InitPaymentOutput
 		input.OrderID,
 		input.RedirectURL,
 		input.WebhookURL,
+		input.Validity,
 		input.Basket,
 	)
 	if err != nil {
@@ -51,6 +53,29 @@ func (s *PaymentService) InitPayment(input InitPaymentInput) (*InitPaymentOutput
 	}, nil
 }
 
+// CancelPayment invalidates the invoice so the customer can't pay an expired order
+func (s *PaymentService) CancelPayment(invoiceID string) error {
+	return s.provider.CancelInvoice(invoiceID)
+}
+
+type RefundPaymentInput struct {
+	Amount int `json:"amount,omitempty"` // coins, the whole invoice when empty
+}
+
+type RefundPaymentOutput struct {
+	Status string `json:"status"`
+}
+
+// RefundPayment returns the money of a paid invoice to the customer
+func (s *PaymentService) RefundPayment(invoiceID string, input RefundPaymentInput) (*RefundPaymentOutput, error) {
+	status, err := s.provider.RefundInvoice(invoiceID, input.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefundPaymentOutput{Status: status}, nil
+}
+
 type WebhookPayload struct {
 	InvoiceID string `json:"invoiceId"`
 	Status    string `json:"status"`
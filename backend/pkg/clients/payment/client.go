@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/tonysanin/brobar/pkg/monobank"
 )
@@ -26,6 +27,7 @@ type InitPaymentInput struct {
 	OrderID     string                 `json:"order_id"`
 	RedirectURL string                 `json:"redirect_url"`
 	WebhookURL  string                 `json:"webhook_url"`
+	Validity    int                    `json:"validity,omitempty"`
 	Basket      []monobank.BasketOrder `json:"basket"`
 }
 
@@ -65,3 +67,56 @@ func (c *Client) InitPayment(input InitPaymentInput) (*InitPaymentOutput, error)
 
 	return &output, nil
 }
+
+func (c *Client) CancelPayment(invoiceID string) error {
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/payments/"+url.PathEscape(invoiceID)+"/cancel", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("payment service error: %s", errResp["error"])
+	}
+
+	return nil
+}
+
+type RefundPaymentOutput struct {
+	Status string `json:"status"`
+}
+
+// RefundPayment returns the whole amount of a paid invoice, the returned status is
+// "processing" until the bank completes the refund
+func (c *Client) RefundPayment(invoiceID string) (*RefundPaymentOutput, error) {
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/payments/"+url.PathEscape(invoiceID)+"/refund", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, fmt.Errorf("payment service error: %s", errResp["error"])
+	}
+
+	var output RefundPaymentOutput
+	if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &output, nil
+}
@@ -5,12 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 const (
 	invoiceCreateUrl = "https://api.monobank.ua/api/merchant/invoice/create"
+	invoiceRemoveUrl = "https://api.monobank.ua/api/merchant/invoice/remove"
+	invoiceCancelUrl = "https://api.monobank.ua/api/merchant/invoice/cancel"
+	invoiceStatusUrl = "https://api.monobank.ua/api/merchant/invoice/status"
 )
 
+// InvoiceStatusReversed is the status of a refunded invoice
+const InvoiceStatusReversed = "reversed"
+
 type ErrorResponse struct {
 	ErrCode string `json:"errCode"`
 	ErrText string `json:"errText"`
@@ -101,3 +108,111 @@ func (a Acquiring) CreateInvoice(invoice *Invoice) (*InvoiceData, error) {
 	}
 	return &response, nil
 }
+
+// RemoveInvoice invalidates an unpaid invoice so it can no longer be paid
+func (a Acquiring) RemoveInvoice(invoiceID string) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]string{"invoiceId": invoiceID}); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, invoiceRemoveUrl, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Token", a.xToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return err
+		}
+		return fmt.Errorf("monobank request error code: %s, error text: %s", errorResponse.ErrCode, errorResponse.ErrText)
+	}
+	return nil
+}
+
+type CancelInvoiceRequest struct {
+	InvoiceID string `json:"invoiceId"`
+	ExtRef    string `json:"extRef,omitempty"`
+	Amount    int    `json:"amount,omitempty"` // coins, the whole amount when empty
+}
+
+type CancelInvoiceResponse struct {
+	Status       string `json:"status"` // processing, success, failure
+	CreatedDate  string `json:"createdDate"`
+	ModifiedDate string `json:"modifiedDate"`
+}
+
+// CancelInvoice refunds a paid invoice, fully or partially
+func (a Acquiring) CancelInvoice(cancel *CancelInvoiceRequest) (*CancelInvoiceResponse, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(cancel); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, invoiceCancelUrl, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Token", a.xToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("monobank request error code: %s, error text: %s", errorResponse.ErrCode, errorResponse.ErrText)
+	}
+	var response CancelInvoiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Status == "failure" {
+		return nil, fmt.Errorf("monobank refund failed for invoice %s", cancel.InvoiceID)
+	}
+	return &response, nil
+}
+
+type InvoiceStatusResponse struct {
+	InvoiceID     string `json:"invoiceId"`
+	Status        string `json:"status"` // created, processing, hold, success, failure, reversed, expired
+	FailureReason string `json:"failureReason,omitempty"`
+	Amount        int    `json:"amount"`
+	FinalAmount   int    `json:"finalAmount"`
+	Reference     string `json:"reference,omitempty"`
+}
+
+// InvoiceStatus returns the current state of an invoice
+func (a Acquiring) InvoiceStatus(invoiceID string) (*InvoiceStatusResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, invoiceStatusUrl+"?invoiceId="+url.QueryEscape(invoiceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Token", a.xToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("monobank request error code: %s, error text: %s", errorResponse.ErrCode, errorResponse.ErrText)
+	}
+	var response InvoiceStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
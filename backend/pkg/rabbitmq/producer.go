@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"github.com/tonysanin/brobar/pkg/helpers"
 	"log"
+	"sync"
 
 	"github.com/streadway/amqp"
 )
 
 type Producer struct {
+	url string
+
+	mu      sync.Mutex
 	conn    *amqp.Connection
 	channel *amqp.Channel
 }
@@ -21,23 +25,52 @@ func NewProducer() *Producer {
 
 	url := fmt.Sprintf("amqp://%s:%s@%s:%s/", user, pass, host, port)
 
-	conn, err := amqp.Dial(url)
-	if err != nil {
+	p := &Producer{url: url}
+	if err := p.connect(); err != nil {
 		log.Fatalf("failed to connect to RabbitMQ: %v", err)
 	}
 
-	ch, err := conn.Channel()
+	return p
+}
+
+func (p *Producer) connect() error {
+	conn, err := amqp.Dial(p.url)
 	if err != nil {
-		log.Fatalf("failed to open a channel: %v", err)
+		return err
 	}
 
-	return &Producer{
-		conn:    conn,
-		channel: ch,
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to open a channel: %w", err)
 	}
+
+	p.conn = conn
+	p.channel = ch
+	return nil
 }
 
+// SendMessage publishes to the queue. A failed publish closes the channel (or the connection
+// is already gone), so it reconnects and tries once more.
 func (p *Producer) SendMessage(queueName QueueName, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	err := p.publish(queueName, body)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("failed to publish to %s, reconnecting: %v", queueName, err)
+	p.closeConnection()
+	if err := p.connect(); err != nil {
+		return fmt.Errorf("failed to reconnect to RabbitMQ: %w", err)
+	}
+
+	return p.publish(queueName, body)
+}
+
+func (p *Producer) publish(queueName QueueName, body string) error {
 	_, err := p.channel.QueueDeclare(
 		string(queueName),
 		true,
@@ -62,7 +95,18 @@ func (p *Producer) SendMessage(queueName QueueName, body string) error {
 	)
 }
 
+func (p *Producer) closeConnection() {
+	if p.channel != nil {
+		_ = p.channel.Close()
+	}
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+}
+
 func (p *Producer) Close() {
-	_ = p.channel.Close()
-	_ = p.conn.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closeConnection()
 }
@@ -3,6 +3,7 @@ package rabbitmq
 type QueueName string
 
 const (
-	QueueTelegram QueueName = "telegram_messages"
-	QueueSyrve    QueueName = "syrve_orders"
+	QueueTelegram    QueueName = "telegram_messages"
+	QueueSyrve       QueueName = "syrve_orders"
+	QueueSyrveCancel QueueName = "syrve_order_cancellations"
 )
@@ -13,6 +13,7 @@ type SuccessResponse struct {
 type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
 }
 
 type Pagination struct {
@@ -70,6 +71,15 @@ func Error(c fiber.Ctx, status int, err error) error {
 	})
 }
 
+// ErrorWithCode adds a machine-readable code so clients can react to specific failures
+func ErrorWithCode(c fiber.Ctx, status int, code string, err error) error {
+	return c.Status(status).JSON(ErrorResponse{
+		Success: false,
+		Error:   err.Error(),
+		Code:    code,
+	})
+}
+
 func NotFound(c fiber.Ctx) error {
 	return Error(c, fiber.StatusNotFound, fiber.ErrNotFound)
 }
@@ -76,6 +76,18 @@ type OrderPayload struct {
     Phone          string           `json:"phone,omitempty"`
     Items          []OrderItem      `json:"items"`
     Comment        string           `json:"comment,omitempty"`
+    DiscountsInfo  *DiscountsInfo   `json:"discountsInfo,omitempty"`
+}
+
+type DiscountsInfo struct {
+    Discounts []OrderDiscount `json:"discounts"`
+}
+
+// OrderDiscount is a manual (RMS) discount of a fixed sum applied to the whole order
+type OrderDiscount struct {
+    DiscountTypeID string  `json:"discountTypeId"`
+    Sum            float64 `json:"sum"`
+    Type           string  `json:"type"` // RMS
 }
 
 type Customer struct {
@@ -93,9 +105,10 @@ type OrderItem struct {
 }
 
 type OrderModifier struct {
-    ProductID      string  `json:"productId,omitempty"`
-    ProductGroupID string  `json:"productGroupId,omitempty"`
-    Amount         float64 `json:"amount"`
+    ProductID      string   `json:"productId,omitempty"`
+    ProductGroupID string   `json:"productGroupId,omitempty"`
+    Amount         float64  `json:"amount"`
+    Price          *float64 `json:"price,omitempty"` // Optional override
 }
 
 type CreateOrderResponse struct {
@@ -117,3 +130,32 @@ func (c *Client) CreateOrder(ctx context.Context, authToken string, req CreateOr
 
     return &resp, nil
 }
+
+// -----------------------------
+// Cancel Order
+// -----------------------------
+
+type CancelOrderRequest struct {
+	OrganizationID string `json:"organizationId"`
+	OrderID        string `json:"orderId"`
+	RemovalTypeID  string `json:"removalTypeId,omitempty"` // write-off reason, Syrve default when empty
+	Comment        string `json:"comment,omitempty"`
+}
+
+type CancelOrderResponse struct {
+	CorrelationID string `json:"correlationId"`
+}
+
+func (c *Client) CancelOrder(ctx context.Context, authToken string, req CancelOrderRequest) (*CancelOrderResponse, error) {
+	respBytes, err := c.doRequest(ctx, http.MethodPost, "/order/cancel", authToken, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CancelOrderResponse
+	if err := json.Unmarshal(respBytes, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
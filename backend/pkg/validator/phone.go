@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// DefaultCountryCode is assumed for numbers typed without one
+const DefaultCountryCode = "380"
+
+// uaSubscriberDigits is the length of a Ukrainian number after +380
+const uaSubscriberDigits = 9
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhone parses a phone number the way customers type it and returns it in E.164,
+// e.g. "+380501234567". Spaces, dashes, dots and brackets are ignored. A number without a
+// country code is taken as Ukrainian: "0501234567", "501234567" and "80501234567" all give
+// "+380501234567".
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+
+	international := false
+	switch {
+	case strings.HasPrefix(phone, "+"):
+		international = true
+		phone = phone[1:]
+	case strings.HasPrefix(phone, "00"):
+		international = true
+		phone = phone[2:]
+	}
+
+	var digits strings.Builder
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	number := digits.String()
+
+	if !international {
+		switch {
+		case len(number) == uaSubscriberDigits:
+			number = DefaultCountryCode + number
+		case len(number) == uaSubscriberDigits+1 && strings.HasPrefix(number, "0"):
+			number = DefaultCountryCode[:2] + number
+		case len(number) == uaSubscriberDigits+2 && strings.HasPrefix(number, "80"):
+			number = DefaultCountryCode[:1] + number
+		case strings.HasPrefix(number, DefaultCountryCode):
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	// E.164 allows up to 15 digits and no leading zero in the country code
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	if strings.HasPrefix(number, DefaultCountryCode) && len(number) != len(DefaultCountryCode)+uaSubscriberDigits {
+		return "", ErrInvalidPhone
+	}
+
+	return "+" + number, nil
+}
+
+// IsPhone checks if the string is a phone number NormalizePhone accepts
+var IsPhone = validation.By(func(value interface{}) error {
+	phone, ok := value.(string)
+	if !ok || phone == "" {
+		return nil
+	}
+	if _, err := NormalizePhone(phone); err != nil {
+		return err
+	}
+	return nil
+})
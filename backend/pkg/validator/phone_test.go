@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "+380501234567", want: "+380501234567"},
+		{in: "380501234567", want: "+380501234567"},
+		{in: "0501234567", want: "+380501234567"},
+		{in: "501234567", want: "+380501234567"},
+		{in: "80501234567", want: "+380501234567"},
+		{in: " +38 (050) 123-45-67 ", want: "+380501234567"},
+		{in: "050.123.45.67", want: "+380501234567"},
+		{in: "00380501234567", want: "+380501234567"},
+		{in: "+48 512 345 678", want: "+48512345678"},
+		{in: "+38050123456", err: true},
+		{in: "+3805012345678", err: true},
+		{in: "12345", err: true},
+		{in: "+0501234567", err: true},
+		{in: "+1234567890123456", err: true},
+		{in: "050-123-45-6x", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizePhone(tt.in)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidPhone)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package validator
 
 import (
 	"errors"
-	"regexp"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
@@ -57,11 +56,6 @@ var IsNonNegative = validation.By(func(value interface{}) error {
 	return nil
 })
 
-var phoneRegex = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
-
-// IsPhone checks if the string is a valid phone number
-var IsPhone = validation.Match(phoneRegex).Error("invalid phone number")
-
 // Helper wrapper to validate a struct that implements the Validatable interface
 type Validatable interface {
 	Validate() error
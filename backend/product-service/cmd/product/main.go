@@ -50,7 +50,10 @@ func main() {
 
 	variationGroupService := services.NewProductVariationGroupService(variationGroupRepository)
 
-	server := api.NewServer(productService, categoryService, variationService, variationGroupService)
+	stockReservationRepository := repositories.NewStockReservationRepository(db)
+	stockService := services.NewStockService(db, productRepository, stockReservationRepository)
+
+	server := api.NewServer(productService, categoryService, variationService, variationGroupService, stockService)
 
 	// Start RabbitMQ Consumer
 	rabbitConsumer, err := consumer.NewConsumer(cfg.RabbitMQURL, productService)
@@ -84,6 +84,26 @@ func (h *ProductHandler) GetProduct(c fiber.Ctx) error {
 	return response.Success(c, product)
 }
 
+// ResolveProducts returns products, variations and groups for a list of IDs.
+// Unknown IDs are left out, callers compare against what they asked for.
+func (h *ProductHandler) ResolveProducts(c fiber.Ctx) error {
+	var req requests.ResolveProductsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	resolved, err := h.service.ResolveProducts(c.Context(), req.ProductIDs, req.VariationIDs)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, resolved)
+}
+
 func (h *ProductHandler) CreateProduct(c fiber.Ctx) error {
 	var req requests.CreateProductRequest
 	if err := c.Bind().Body(&req); err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/gofiber/fiber/v3"
 	"github.com/tonysanin/brobar/pkg/response"
+	"github.com/tonysanin/brobar/pkg/validator"
 	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
 	"github.com/tonysanin/brobar/product-service/internal/models"
 	"github.com/tonysanin/brobar/product-service/internal/services"
@@ -72,6 +73,7 @@ func (h *ProductVariationHandler) CreateVariation(c fiber.Ctx) error {
 		),
 		validation.Field(&variation.Name, validation.Required, validation.Length(1, 255)),
 		validation.Field(&variation.ExternalID, validation.Required, validation.Length(0, 100)),
+		validation.Field(&variation.Price, validator.IsNonNegative),
 	)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err)
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/response"
+	"github.com/tonysanin/brobar/product-service/internal/api/requests"
+	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
+	"github.com/tonysanin/brobar/product-service/internal/services"
+)
+
+type StockHandler struct {
+	service *services.StockService
+}
+
+func NewStockHandler(s *services.StockService) *StockHandler {
+	return &StockHandler{service: s}
+}
+
+func (h *StockHandler) Reserve(c fiber.Ctx) error {
+	var req requests.ReserveStockRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	items := make([]services.StockItem, len(req.Items))
+	for i, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return response.BadRequest(c, err)
+		}
+		items[i] = services.StockItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	if err := h.service.Reserve(c.Context(), req.OrderID, items); err != nil {
+		if errors.Is(err, customerrors.InsufficientStock) {
+			return response.Error(c, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, customerrors.ProductNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, fiber.Map{"status": "reserved"})
+}
+
+func (h *StockHandler) Commit(c fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("order_id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order ID"))
+	}
+
+	if err := h.service.Commit(c.Context(), orderID); err != nil {
+		if errors.Is(err, customerrors.StockReservationNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, fiber.Map{"status": "committed"})
+}
+
+func (h *StockHandler) Release(c fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("order_id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid order ID"))
+	}
+
+	if err := h.service.Release(c.Context(), orderID); err != nil {
+		if errors.Is(err, customerrors.StockReservationNotFound) {
+			return response.NotFound(c)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err)
+	}
+
+	return response.Success(c, fiber.Map{"status": "released"})
+}
@@ -10,10 +10,11 @@ import (
 )
 
 type NestedVariationRequest struct {
-	Name         string `json:"name" form:"name"`
-	ExternalID   string `json:"external_id" form:"external_id"`
-	DefaultValue *int   `json:"default_value" form:"default_value"`
-	Show         bool   `json:"show" form:"show"`
+	Name         string  `json:"name" form:"name"`
+	ExternalID   string  `json:"external_id" form:"external_id"`
+	DefaultValue *int    `json:"default_value" form:"default_value"`
+	Show         bool    `json:"show" form:"show"`
+	Price        float64 `json:"price" form:"price"`
 }
 
 type NestedVariationGroupRequest struct {
@@ -69,6 +70,7 @@ func (r CreateProductRequest) ToModel() *models.Product {
 				ExternalID:   v.ExternalID,
 				DefaultValue: v.DefaultValue,
 				Show:         v.Show,
+				Price:        v.Price,
 			}
 		}
 		p.VariationGroups[i] = vg
@@ -0,0 +1,19 @@
+package requests
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+type ResolveProductsRequest struct {
+	ProductIDs   []uuid.UUID `json:"product_ids"`
+	VariationIDs []uuid.UUID `json:"variation_ids,omitempty"`
+}
+
+func (r ResolveProductsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ProductIDs, validation.Required, validation.Length(1, 200), validation.Each(validator.IsUUID)),
+		validation.Field(&r.VariationIDs, validation.Length(0, 200), validation.Each(validator.IsUUID)),
+	)
+}
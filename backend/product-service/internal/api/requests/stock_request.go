@@ -0,0 +1,31 @@
+package requests
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+type ReserveStockItemRequest struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  float64   `json:"quantity"`
+}
+
+type ReserveStockRequest struct {
+	OrderID uuid.UUID                 `json:"order_id"`
+	Items   []ReserveStockItemRequest `json:"items"`
+}
+
+func (r ReserveStockItemRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ProductID, validation.Required, validator.IsUUID),
+		validation.Field(&r.Quantity, validation.Required, validation.Min(0.0).Exclusive()),
+	)
+}
+
+func (r ReserveStockRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.OrderID, validation.Required, validator.IsUUID),
+		validation.Field(&r.Items, validation.Required, validation.Length(1, 100)),
+	)
+}
@@ -14,11 +14,13 @@ type Server struct {
 	categoryService       *services.CategoryService
 	variationService      *services.ProductVariationService
 	variationGroupService *services.ProductVariationGroupService
+	stockService          *services.StockService
 	productHandler        *handlers.ProductHandler
 	categoryHandler       *handlers.CategoryHandler
 	variationHandler      *handlers.ProductVariationHandler
 	variationGroupHandler *handlers.ProductVariationGroupHandler
 	menuHandler           *handlers.MenuHandler
+	stockHandler          *handlers.StockHandler
 }
 
 func NewServer(
@@ -26,6 +28,7 @@ func NewServer(
 	categoryService *services.CategoryService,
 	variationService *services.ProductVariationService,
 	variationGroupService *services.ProductVariationGroupService,
+	stockService *services.StockService,
 ) *Server {
 	s := &Server{
 		app: fiber.New(fiber.Config{
@@ -36,6 +39,7 @@ func NewServer(
 		categoryService:       categoryService,
 		variationService:      variationService,
 		variationGroupService: variationGroupService,
+		stockService:          stockService,
 	}
 
 	s.app.Use(compress.New(compress.Config{
@@ -47,6 +51,7 @@ func NewServer(
 	s.variationHandler = handlers.NewProductVariationHandler(variationService, variationGroupService)
 	s.variationGroupHandler = handlers.NewProductVariationGroupHandler(variationGroupService, productService)
 	s.menuHandler = handlers.NewMenuHandler(categoryService)
+	s.stockHandler = handlers.NewStockHandler(stockService)
 
 	s.SetupRoutes()
 
@@ -66,6 +71,7 @@ func (s *Server) SetupRoutes() {
 	productGroup.Get("/", s.productHandler.GetProducts)
 	productGroup.Get("/:id", s.productHandler.GetProduct)
 	productGroup.Post("/", s.productHandler.CreateProduct)
+	productGroup.Post("/resolve", s.productHandler.ResolveProducts)
 	productGroup.Put("/:id", s.productHandler.UpdateProduct)
 	productGroup.Delete("/:product_id/variation-groups", s.variationGroupHandler.DeleteGroupsByProduct)
 	productGroup.Delete("/:id", s.productHandler.DeleteProduct)
@@ -91,6 +97,11 @@ func (s *Server) SetupRoutes() {
 	groupGroup.Post("/", s.variationGroupHandler.CreateGroup)
 	groupGroup.Put("/:id", s.variationGroupHandler.UpdateGroup)
 	groupGroup.Delete("/:id", s.variationGroupHandler.DeleteGroup)
+
+	reservationGroup := s.app.Group("/stock/reservations")
+	reservationGroup.Post("/", s.stockHandler.Reserve)
+	reservationGroup.Post("/:order_id/commit", s.stockHandler.Commit)
+	reservationGroup.Post("/:order_id/release", s.stockHandler.Release)
 }
 
 func (s *Server) Listen(address string) error {
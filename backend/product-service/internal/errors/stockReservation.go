@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	InsufficientStock        = errors.New("insufficient stock")
+	StockReservationNotFound = errors.New("stock reservation not found")
+)
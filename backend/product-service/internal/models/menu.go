@@ -10,6 +10,7 @@ type MenuVariation struct {
 	ExternalID   string    `json:"external_id,omitempty" db:"external_id"`
 	DefaultValue *int      `json:"default_value,omitempty" db:"default_value"`
 	Show         bool      `json:"show" db:"show"`
+	Price        float64   `json:"price" db:"price"`
 }
 
 // MenuVariationGroup represents a variation group with its variations in the menu tree
@@ -11,4 +11,5 @@ type ProductVariation struct {
 	DefaultValue *int      `json:"default_value" db:"default_value"`
 	Show         bool      `json:"show" db:"show"`
 	Name         string    `json:"name" db:"name" validate:"required,min=1,max=255"`
+	Price        float64   `json:"price" db:"price"` // surcharge added to the product price
 }
@@ -0,0 +1,8 @@
+package models
+
+// ResolvedProducts is everything needed to price a cart, fetched in one round-trip
+type ResolvedProducts struct {
+	Products        []Product               `json:"products"`
+	Variations      []ProductVariation      `json:"variations"`
+	VariationGroups []ProductVariationGroup `json:"variation_groups"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockReservationStatus string
+
+const (
+	StockReservationReserved  StockReservationStatus = "reserved"
+	StockReservationCommitted StockReservationStatus = "committed"
+	StockReservationReleased  StockReservationStatus = "released"
+)
+
+type StockReservation struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	OrderID   uuid.UUID              `json:"order_id" db:"order_id"`
+	ProductID uuid.UUID              `json:"product_id" db:"product_id"`
+	Quantity  float64                `json:"quantity" db:"quantity"`
+	Status    StockReservationStatus `json:"status" db:"status"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at" db:"updated_at"`
+}
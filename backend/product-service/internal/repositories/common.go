@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -19,3 +20,11 @@ type dbExecutor interface {
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
 }
+
+func uuidStrings(ids []uuid.UUID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
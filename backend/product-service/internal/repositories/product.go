@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
 	"github.com/tonysanin/brobar/product-service/internal/models"
 )
@@ -260,3 +261,72 @@ func (r *ProductRepository) UpdateProductStock(ctx context.Context, externalID s
 
 	return nil
 }
+
+// DecrementStock atomically takes quantity from a product's stock.
+// Products with NULL stock are unlimited and always succeed; false means not enough stock left.
+func (r *ProductRepository) DecrementStock(ctx context.Context, id uuid.UUID, quantity float64) (bool, error) {
+	const query = `
+		UPDATE products SET stock = CASE WHEN stock IS NULL THEN NULL ELSE stock - $1 END
+		WHERE id = $2 AND (stock IS NULL OR stock >= $1)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, quantity, id)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return false, fmt.Errorf("database query timed out")
+		}
+		return false, fmt.Errorf("failed to decrement product stock: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// IncrementStock returns quantity to a product's stock, leaving unlimited products untouched
+func (r *ProductRepository) IncrementStock(ctx context.Context, id uuid.UUID, quantity float64) error {
+	const query = `UPDATE products SET stock = stock + $1 WHERE id = $2 AND stock IS NOT NULL`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, quantity, id)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to increment product stock: %w", err)
+	}
+
+	return nil
+}
+
+// GetProductsByIDs returns the products with the given IDs, missing IDs are skipped
+func (r *ProductRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Product, error) {
+	const query = `SELECT * FROM products WHERE id = ANY($1::uuid[])`
+
+	var products []models.Product
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &products, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	if products == nil {
+		return []models.Product{}, nil
+	}
+
+	return products, nil
+}
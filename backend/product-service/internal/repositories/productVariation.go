@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
 	"github.com/tonysanin/brobar/product-service/internal/models"
 )
@@ -72,13 +73,14 @@ func (r *ProductVariationRepository) GetByID(ctx context.Context, id uuid.UUID)
 func (r *ProductVariationRepository) Create(ctx context.Context, variation *models.ProductVariation) error {
 	const query = `
 		INSERT INTO product_variations (
-			id, group_id, external_id, default_value, show, name
+			id, group_id, external_id, default_value, show, name, price
 		) VALUES (
-			:id, :group_id, :external_id, :default_value, :show, :name
+			:id, :group_id, :external_id, :default_value, :show, :name, :price
 		) ON CONFLICT (group_id, external_id) DO UPDATE SET
 			default_value = EXCLUDED.default_value,
 			show = EXCLUDED.show,
-			name = EXCLUDED.name
+			name = EXCLUDED.name,
+			price = EXCLUDED.price
 	`
 
 	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
@@ -102,7 +104,8 @@ func (r *ProductVariationRepository) Update(ctx context.Context, variation *mode
 			external_id = :external_id,
 			default_value = :default_value,
 			show = :show,
-			name = :name
+			name = :name,
+			price = :price
 		WHERE id = :id
 	`
 
@@ -152,3 +155,51 @@ func (r *ProductVariationRepository) Delete(ctx context.Context, id uuid.UUID) e
 
 	return nil
 }
+
+// GetByIDs returns the product variations with the given IDs, missing IDs are skipped
+func (r *ProductVariationRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.ProductVariation, error) {
+	const query = `SELECT * FROM product_variations WHERE id = ANY($1::uuid[])`
+
+	var variations []models.ProductVariation
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &variations, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get product variations: %w", err)
+	}
+
+	if variations == nil {
+		return []models.ProductVariation{}, nil
+	}
+
+	return variations, nil
+}
+
+// GetAllByGroupIDs returns the product variations of all the given groups
+func (r *ProductVariationRepository) GetAllByGroupIDs(ctx context.Context, ids []uuid.UUID) ([]models.ProductVariation, error) {
+	const query = `SELECT * FROM product_variations WHERE group_id = ANY($1::uuid[])`
+
+	var variations []models.ProductVariation
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &variations, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get product variations: %w", err)
+	}
+
+	if variations == nil {
+		return []models.ProductVariation{}, nil
+	}
+
+	return variations, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
 	"github.com/tonysanin/brobar/product-service/internal/models"
 )
@@ -176,3 +177,51 @@ func (r *ProductVariationGroupRepository) DeleteByProductID(ctx context.Context,
 
 	return nil
 }
+
+// GetByIDs returns the product variation groups with the given IDs, missing IDs are skipped
+func (r *ProductVariationGroupRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.ProductVariationGroup, error) {
+	const query = `SELECT * FROM product_variation_groups WHERE id = ANY($1::uuid[])`
+
+	var groups []models.ProductVariationGroup
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &groups, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get product variation groups: %w", err)
+	}
+
+	if groups == nil {
+		return []models.ProductVariationGroup{}, nil
+	}
+
+	return groups, nil
+}
+
+// GetAllByProductIDs returns the product variation groups of all the given products
+func (r *ProductVariationGroupRepository) GetAllByProductIDs(ctx context.Context, ids []uuid.UUID) ([]models.ProductVariationGroup, error) {
+	const query = `SELECT * FROM product_variation_groups WHERE product_id = ANY($1::uuid[])`
+
+	var groups []models.ProductVariationGroup
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &groups, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get product variation groups: %w", err)
+	}
+
+	if groups == nil {
+		return []models.ProductVariationGroup{}, nil
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/product-service/internal/models"
+)
+
+type StockReservationRepository struct {
+	db dbExecutor
+}
+
+func NewStockReservationRepository(db *sqlx.DB) *StockReservationRepository {
+	return &StockReservationRepository{db: db}
+}
+
+func (r *StockReservationRepository) WithTx(tx *sqlx.Tx) *StockReservationRepository {
+	return &StockReservationRepository{db: tx}
+}
+
+// GetByOrderIDForUpdate loads the order's reservations and locks them until the transaction ends
+func (r *StockReservationRepository) GetByOrderIDForUpdate(ctx context.Context, orderID uuid.UUID) ([]models.StockReservation, error) {
+	const query = `SELECT * FROM stock_reservations WHERE order_id = $1 FOR UPDATE`
+
+	var reservations []models.StockReservation
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.SelectContext(ctx, &reservations, query, orderID)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("database query timed out")
+		}
+		return nil, fmt.Errorf("failed to get stock reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+func (r *StockReservationRepository) Create(ctx context.Context, reservation *models.StockReservation) error {
+	const query = `
+		INSERT INTO stock_reservations (id, order_id, product_id, quantity, status, created_at, updated_at)
+		VALUES (:id, :order_id, :product_id, :quantity, :status, :created_at, :updated_at)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, query, reservation)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to create stock reservation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StockReservationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.StockReservationStatus) error {
+	const query = `UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("database query timed out")
+		}
+		return fmt.Errorf("failed to update stock reservation: %w", err)
+	}
+
+	return nil
+}
@@ -52,6 +52,108 @@ func (s *ProductService) GetProductsWithPagination(ctx context.Context, limit, o
 	return products, totalCount, nil
 }
 
+// ResolveProducts loads products with their variation groups (and the groups' variations),
+// plus the requested variations and their groups, in one call
+func (s *ProductService) ResolveProducts(ctx context.Context, productIDs, variationIDs []uuid.UUID) (*models.ResolvedProducts, error) {
+	products, err := s.repo.GetProductsByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &models.ResolvedProducts{
+		Products:        products,
+		Variations:      []models.ProductVariation{},
+		VariationGroups: []models.ProductVariationGroup{},
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	groups, err := s.loadGroupsWithVariations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsByProduct := make(map[uuid.UUID][]models.ProductVariationGroup)
+	groupsByID := make(map[uuid.UUID]models.ProductVariationGroup)
+	for _, group := range groups {
+		groupsByProduct[group.ProductID] = append(groupsByProduct[group.ProductID], group)
+		groupsByID[group.ID] = group
+	}
+	for i := range resolved.Products {
+		resolved.Products[i].VariationGroups = groupsByProduct[resolved.Products[i].ID]
+	}
+
+	if len(variationIDs) == 0 {
+		return resolved, nil
+	}
+
+	variations, err := s.variationRepo.GetByIDs(ctx, variationIDs)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Variations = variations
+
+	seen := make(map[uuid.UUID]bool)
+	var groupIDs []uuid.UUID
+	for _, variation := range variations {
+		if seen[variation.GroupID] {
+			continue
+		}
+		seen[variation.GroupID] = true
+		if group, ok := groupsByID[variation.GroupID]; ok {
+			resolved.VariationGroups = append(resolved.VariationGroups, group)
+		} else {
+			groupIDs = append(groupIDs, variation.GroupID)
+		}
+	}
+
+	// Groups of products that were not requested
+	if len(groupIDs) > 0 {
+		groups, err := s.variationGroupRepo.GetByIDs(ctx, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		resolved.VariationGroups = append(resolved.VariationGroups, groups...)
+	}
+
+	return resolved, nil
+}
+
+func (s *ProductService) loadGroupsWithVariations(ctx context.Context, productIDs []uuid.UUID) ([]models.ProductVariationGroup, error) {
+	if len(productIDs) == 0 {
+		return []models.ProductVariationGroup{}, nil
+	}
+
+	groups, err := s.variationGroupRepo.GetAllByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	groupIDs := make([]uuid.UUID, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+	}
+	variations, err := s.variationRepo.GetAllByGroupIDs(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	variationsByGroup := make(map[uuid.UUID][]models.ProductVariation)
+	for _, variation := range variations {
+		variationsByGroup[variation.GroupID] = append(variationsByGroup[variation.GroupID], variation)
+	}
+	for i := range groups {
+		groups[i].Variations = variationsByGroup[groups[i].ID]
+	}
+
+	return groups, nil
+}
+
 func (s *ProductService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
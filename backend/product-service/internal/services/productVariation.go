@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/validator"
 	"github.com/tonysanin/brobar/product-service/internal/models"
 	"github.com/tonysanin/brobar/product-service/internal/repositories"
 )
@@ -70,6 +71,7 @@ func (s *ProductVariationService) Update(ctx context.Context, id string, updated
 	}
 	existing.DefaultValue = updated.DefaultValue
 	existing.Show = updated.Show
+	existing.Price = updated.Price
 	if updated.GroupID != uuid.Nil {
 		existing.GroupID = updated.GroupID
 	}
@@ -78,6 +80,7 @@ func (s *ProductVariationService) Update(ctx context.Context, id string, updated
 		validation.Field(&existing.GroupID, validation.Required),
 		validation.Field(&existing.Name, validation.Required, validation.Length(1, 255)),
 		validation.Field(&existing.ExternalID, validation.Length(0, 100)),
+		validation.Field(&existing.Price, validator.IsNonNegative),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/product-service/internal/errors"
+	"github.com/tonysanin/brobar/product-service/internal/models"
+	"github.com/tonysanin/brobar/product-service/internal/repositories"
+)
+
+type StockService struct {
+	db              *sqlx.DB
+	productRepo     *repositories.ProductRepository
+	reservationRepo *repositories.StockReservationRepository
+}
+
+func NewStockService(
+	db *sqlx.DB,
+	productRepo *repositories.ProductRepository,
+	reservationRepo *repositories.StockReservationRepository,
+) *StockService {
+	return &StockService{
+		db:              db,
+		productRepo:     productRepo,
+		reservationRepo: reservationRepo,
+	}
+}
+
+type StockItem struct {
+	ProductID uuid.UUID
+	Quantity  float64
+}
+
+// Reserve takes the requested quantities out of stock for the order in one transaction.
+// Reserving an order that already has reservations is a no-op, so retries are safe.
+func (s *StockService) Reserve(ctx context.Context, orderID uuid.UUID, items []StockItem) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	txProductRepo := s.productRepo.WithTx(tx)
+	txReservationRepo := s.reservationRepo.WithTx(tx)
+
+	existing, err := txReservationRepo.GetByOrderIDForUpdate(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	// Merge lines of the same product (e.g. different variations)
+	quantities := make(map[uuid.UUID]float64)
+	var order []uuid.UUID
+	for _, item := range items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			order = append(order, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	now := time.Now()
+	for _, productID := range order {
+		quantity := quantities[productID]
+
+		ok, err := txProductRepo.DecrementStock(ctx, productID, quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			product, err := txProductRepo.GetProductByID(ctx, productID)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", customerrors.InsufficientStock, product.Name)
+		}
+
+		reservation := &models.StockReservation{
+			ID:        uuid.New(),
+			OrderID:   orderID,
+			ProductID: productID,
+			Quantity:  quantity,
+			Status:    models.StockReservationReserved,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := txReservationRepo.Create(ctx, reservation); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Commit marks the order's reservations as final; stock was already taken on reserve
+func (s *StockService) Commit(ctx context.Context, orderID uuid.UUID) error {
+	return s.transition(ctx, orderID, models.StockReservationCommitted, nil)
+}
+
+// Release returns reserved or committed quantities back to stock
+func (s *StockService) Release(ctx context.Context, orderID uuid.UUID) error {
+	return s.transition(ctx, orderID, models.StockReservationReleased, func(productRepo *repositories.ProductRepository, r models.StockReservation) error {
+		return productRepo.IncrementStock(ctx, r.ProductID, r.Quantity)
+	})
+}
+
+func (s *StockService) transition(
+	ctx context.Context,
+	orderID uuid.UUID,
+	to models.StockReservationStatus,
+	apply func(productRepo *repositories.ProductRepository, r models.StockReservation) error,
+) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	txProductRepo := s.productRepo.WithTx(tx)
+	txReservationRepo := s.reservationRepo.WithTx(tx)
+
+	reservations, err := txReservationRepo.GetByOrderIDForUpdate(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(reservations) == 0 {
+		return customerrors.StockReservationNotFound
+	}
+
+	for _, r := range reservations {
+		// Released is final; committed can only be released
+		if r.Status == models.StockReservationReleased || r.Status == to {
+			continue
+		}
+		if r.Status == models.StockReservationCommitted && to != models.StockReservationReleased {
+			continue
+		}
+
+		if apply != nil {
+			if err := apply(txProductRepo, r); err != nil {
+				return err
+			}
+		}
+		if err := txReservationRepo.UpdateStatus(ctx, r.ID, to); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
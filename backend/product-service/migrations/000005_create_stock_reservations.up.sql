@@ -0,0 +1,12 @@
+CREATE TABLE stock_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity NUMERIC NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'reserved',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (order_id, product_id)
+);
+
+CREATE INDEX idx_stock_reservations_order_id ON stock_reservations(order_id);
@@ -0,0 +1 @@
+ALTER TABLE product_variations DROP COLUMN IF EXISTS price;
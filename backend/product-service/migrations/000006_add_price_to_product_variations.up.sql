@@ -0,0 +1 @@
+ALTER TABLE product_variations ADD COLUMN price NUMERIC(10,2) NOT NULL DEFAULT 0;
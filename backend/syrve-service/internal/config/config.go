@@ -9,6 +9,8 @@ type Config struct {
 	APILogin       string
 	OrganizationID string
 	OrderServiceURL string
+	PromoDiscountTypeID string
+	RemovalTypeID string
 }
 
 func NewConfig() *Config {
@@ -17,5 +19,7 @@ func NewConfig() *Config {
 		APILogin:        helpers.GetEnv("SYRVE_TOKEN", ""),
 		OrganizationID:  helpers.GetEnv("SYRVE_ORGANIZATION", ""),
 		OrderServiceURL: helpers.GetEnv("ORDER_SERVICE_URL", "http://order-service-dev:3003"),
+		PromoDiscountTypeID: helpers.GetEnv("SYRVE_PROMO_DISCOUNT_TYPE_ID", ""),
+		RemovalTypeID: helpers.GetEnv("SYRVE_REMOVAL_TYPE_ID", ""),
 	}
 }
@@ -16,13 +16,15 @@ import (
 )
 
 type Consumer struct {
-	client       *syrve.Client
-	producer     *rabbitmq.Producer
-	currentOrder *models.OrderEvent // Not used, maybe for debugging
+	client              *syrve.Client
+	producer            *rabbitmq.Producer
+	promoDiscountTypeID string
+	removalTypeID       string
+	currentOrder        *models.OrderEvent // Not used, maybe for debugging
 }
 
-func NewConsumer(client *syrve.Client, producer *rabbitmq.Producer) *Consumer {
-	return &Consumer{client: client, producer: producer}
+func NewConsumer(client *syrve.Client, producer *rabbitmq.Producer, promoDiscountTypeID, removalTypeID string) *Consumer {
+	return &Consumer{client: client, producer: producer, promoDiscountTypeID: promoDiscountTypeID, removalTypeID: removalTypeID}
 }
 
 func (c *Consumer) Start(uri string) {
@@ -38,6 +40,11 @@ func (c *Consumer) Start(uri string) {
 		log.Fatalf("Failed to consume messages: %v", err)
 	}
 
+	cancelMsgs, err := rmqConsumer.Consume(rabbitmq.QueueSyrveCancel)
+	if err != nil {
+		log.Fatalf("Failed to consume order cancellations: %v", err)
+	}
+
 	// Consumer 2: Sync Triggers
 	syncMsgs, err := rmqConsumer.Consume("syrve.sync.start")
 	if err != nil {
@@ -65,6 +72,20 @@ func (c *Consumer) Start(uri string) {
 				} else {
 					log.Printf("Successfully sent order %s to Syrve", order.ID)
 				}
+			case d := <-cancelMsgs:
+				log.Printf("Received order cancellation: %s", d.Body)
+
+				var cancel models.OrderCancelEvent
+				if err := json.Unmarshal(d.Body, &cancel); err != nil {
+					log.Printf("Error unmarshalling order cancellation: %v", err)
+					continue
+				}
+
+				if err := c.cancelOrder(context.Background(), &cancel); err != nil {
+					log.Printf("Error cancelling order %s: %v", cancel.OrderID, err)
+				} else {
+					log.Printf("Successfully cancelled order %s in Syrve", cancel.OrderID)
+				}
 			case d := <-syncMsgs:
 				log.Printf("Received sync trigger: %s", d.Body)
 				c.handleSync(d)
@@ -370,6 +391,76 @@ func (c *Consumer) processOrder(ctx context.Context, order *models.OrderEvent) e
 		menuMap[p.ID] = p
 	}
 
+	// addVariation attaches a variation as a modifier of the item (or replaces the product
+	// when Syrve has it as a product). Amount 0 means the menu default amount, a nil price
+	// leaves the modifier at its menu price.
+	addVariation := func(syrveItem *syrve.OrderItem, itemName, externalID, vName string, amount float64, price *float64) {
+		vID := findIDByAny(externalID, vName)
+		if vID == "" {
+			return
+		}
+		vProduct, exists := menuMap[vID]
+		if exists && vProduct.Type == "modifier" {
+			// Find if it belongs to a group modifier for the main product
+			var foundGroupID string
+			defaultAmount := 1.0
+
+			if mainProd, exists := menuMap[syrveItem.ProductID]; exists {
+				// 1. Check Group Modifiers
+				for _, gm := range mainProd.GroupModifiers {
+					for _, cm := range gm.ChildModifiers {
+						if cm.ID == vID {
+							foundGroupID = gm.ID
+							if cm.DefaultAmount != nil {
+								defaultAmount = float64(*cm.DefaultAmount)
+							} else if cm.MinAmount > 0 {
+								defaultAmount = float64(cm.MinAmount)
+							}
+							break
+						}
+					}
+					if foundGroupID != "" {
+						break
+					}
+				}
+
+				// 2. Check Simple Modifiers if not found in groups
+				if foundGroupID == "" {
+					for _, m := range mainProd.Modifiers {
+						if m.ID == vID {
+							if m.DefaultAmount != nil {
+								defaultAmount = float64(*m.DefaultAmount)
+							} else if m.MinAmount > 0 {
+								defaultAmount = float64(m.MinAmount)
+							}
+							break
+						}
+					}
+				}
+			}
+
+			if amount <= 0 {
+				amount = defaultAmount
+			}
+
+			log.Printf("Adding variation %s as MODIFIER to %s (GroupID: %s, Amount: %.0f)", vName, itemName, foundGroupID, amount)
+			syrveItem.Modifiers = append(syrveItem.Modifiers, syrve.OrderModifier{
+				ProductID:      vID,
+				ProductGroupID: foundGroupID,
+				Amount:         amount,
+				Price:          price,
+			})
+		} else if exists {
+			log.Printf("Variation %s resolved to Product Type %s, REPLACING main product %s", vName, vProduct.Type, itemName)
+			syrveItem.ProductID = vID
+			// The surcharge can't go on a modifier, so it stays in the product price
+			if price != nil && syrveItem.Price != nil {
+				linePrice := *syrveItem.Price + *price*amount
+				syrveItem.Price = &linePrice
+			}
+		}
+	}
+
 	var syrveItems []syrve.OrderItem
 	
 	for _, item := range order.Items {
@@ -384,73 +475,28 @@ func (c *Consumer) processOrder(ctx context.Context, order *models.OrderEvent) e
 		// Split items into separate lines (Quantity 1) to satisfy Syrve restrictions (Commodity Marks)
 		// and simplify modifier logic.
 		for i := 0; i < item.Quantity; i++ {
+			// Variation surcharges are sent as modifier prices, the line keeps the product price
+			basePrice := item.Price - item.VariationsPrice
 			syrveItem := syrve.OrderItem{
 				ProductID: syrveProductID,
 				Amount:    1.0, 
-				Price:     &item.Price,
+				Price:     &basePrice,
 				Type:      "Product",
 				Modifiers: []syrve.OrderModifier{},
 			}
 
-			// Handle Variation/Modifier from event
-			if item.ProductVariationExternalID != nil && *item.ProductVariationExternalID != "" {
+			// Handle Variations/Modifiers from event
+			if len(item.Variations) > 0 {
+				for _, v := range item.Variations {
+					price := v.Price
+					addVariation(&syrveItem, item.Name, v.ExternalID, v.Name, float64(v.Quantity), &price)
+				}
+			} else if item.ProductVariationExternalID != nil && *item.ProductVariationExternalID != "" {
 				vName := ""
 				if item.ProductVariationName != nil {
 					vName = *item.ProductVariationName
 				}
-				vID := findIDByAny(*item.ProductVariationExternalID, vName)
-				if vID != "" {
-					vProduct, exists := menuMap[vID]
-					if exists && vProduct.Type == "modifier" {
-						// Find if it belongs to a group modifier for the main product
-						var foundGroupID string
-						defaultAmount := 1.0
-
-						if mainProd, exists := menuMap[syrveItem.ProductID]; exists {
-							// 1. Check Group Modifiers
-							for _, gm := range mainProd.GroupModifiers {
-								for _, cm := range gm.ChildModifiers {
-									if cm.ID == vID {
-										foundGroupID = gm.ID
-										if cm.DefaultAmount != nil {
-											defaultAmount = float64(*cm.DefaultAmount)
-										} else if cm.MinAmount > 0 {
-											defaultAmount = float64(cm.MinAmount)
-										}
-										break
-									}
-								}
-								if foundGroupID != "" {
-									break
-								}
-							}
-
-							// 2. Check Simple Modifiers if not found in groups
-							if foundGroupID == "" {
-								for _, m := range mainProd.Modifiers {
-									if m.ID == vID {
-										if m.DefaultAmount != nil {
-											defaultAmount = float64(*m.DefaultAmount)
-										} else if m.MinAmount > 0 {
-											defaultAmount = float64(m.MinAmount)
-										}
-										break
-									}
-								}
-							}
-						}
-
-						log.Printf("Adding variation %s as MODIFIER to %s (GroupID: %s, Amount: %.0f)", vName, item.Name, foundGroupID, defaultAmount)
-						syrveItem.Modifiers = append(syrveItem.Modifiers, syrve.OrderModifier{
-							ProductID:      vID,
-							ProductGroupID: foundGroupID,
-							Amount:         defaultAmount,
-						})
-					} else if exists {
-						log.Printf("Variation %s resolved to Product Type %s, REPLACING main product %s", vName, vProduct.Type, item.Name)
-						syrveItem.ProductID = vID
-					}
-				}
+				addVariation(&syrveItem, item.Name, *item.ProductVariationExternalID, vName, 0, nil)
 			}
 
 			// Automatic Enrichment: Add Mandatory Modifiers
@@ -522,6 +568,33 @@ func (c *Consumer) processOrder(ctx context.Context, order *models.OrderEvent) e
 		},
 	}
 	
+	// Promo discount and redeemed loyalty points go as a single order-level discount line
+	if discount := order.Discount + float64(order.LoyaltyPoints); discount > 0 {
+		if c.promoDiscountTypeID != "" {
+			payload.Order.DiscountsInfo = &syrve.DiscountsInfo{
+				Discounts: []syrve.OrderDiscount{{
+					DiscountTypeID: c.promoDiscountTypeID,
+					Sum:            discount,
+					Type:           "RMS",
+				}},
+			}
+		} else {
+			log.Printf("Warning: SYRVE_PROMO_DISCOUNT_TYPE_ID is not set, discount for order %s added to comment", order.ID)
+		}
+
+		var notes []string
+		if order.Discount > 0 {
+			notes = append(notes, fmt.Sprintf("Промокод %s: знижка %.2f грн", order.Promo, order.Discount))
+		}
+		if order.LoyaltyPoints > 0 {
+			notes = append(notes, fmt.Sprintf("Оплачено бонусами: %d грн", order.LoyaltyPoints))
+		}
+		if payload.Order.Comment != "" {
+			payload.Order.Comment += "\n"
+		}
+		payload.Order.Comment += strings.Join(notes, "\n")
+	}
+
 	// Add External ID to link them
 	payload.Order.ID = order.ID.String()
 
@@ -534,3 +607,36 @@ func (c *Consumer) processOrder(ctx context.Context, order *models.OrderEvent) e
 
 	return nil
 }
+
+// cancelOrder cancels the Syrve order, it was created with our order ID as its ID
+func (c *Consumer) cancelOrder(ctx context.Context, cancel *models.OrderCancelEvent) error {
+	tokenResp, err := c.client.GetAccessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("auth error: %w", err)
+	}
+	token := tokenResp.Token
+	orgID := c.client.OrganizationID
+
+	if orgID == "" {
+		orgs, err := c.client.GetOrganizations(ctx, token, syrve.OrganizationsRequest{})
+		if err != nil {
+			return err
+		}
+		if len(orgs.Organizations) == 0 {
+			return fmt.Errorf("no organizations found")
+		}
+		orgID = orgs.Organizations[0].ID
+	}
+
+	_, err = c.client.CancelOrder(ctx, token, syrve.CancelOrderRequest{
+		OrganizationID: orgID,
+		OrderID:        cancel.OrderID.String(),
+		RemovalTypeID:  c.removalTypeID,
+		Comment:        cancel.Reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return nil
+}
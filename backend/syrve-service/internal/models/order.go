@@ -34,6 +34,8 @@ type OrderEvent struct {
 	PaymentMethod     string       `json:"payment_method,omitempty"`
 	Zone              *string      `json:"zone,omitempty"`
 	InvoiceID         *string      `json:"invoice_id,omitempty"`
+	Discount          float64      `json:"discount"`
+	LoyaltyPoints     int          `json:"loyalty_points"`
 	
 	Items []OrderItem `json:"items"`
 }
@@ -48,10 +50,30 @@ type OrderItem struct {
 	TotalPrice                 float64    `json:"total_price"`
 	Weight                     float64    `json:"weight"`
 	TotalWeight                float64    `json:"total_weight"`
+	Discount                   float64    `json:"discount"`
+	VariationsPrice            float64    `json:"variations_price"`
 
 	ProductVariationGroupID    *uuid.UUID `json:"product_variation_group_id,omitempty"`
 	ProductVariationGroupName  *string    `json:"product_variation_group_name,omitempty"`
 	ProductVariationID         *uuid.UUID `json:"product_variation_id,omitempty"`
 	ProductVariationExternalID *string    `json:"product_variation_external_id,omitempty"`
 	ProductVariationName       *string    `json:"product_variation_name,omitempty"`
+
+	Variations []OrderItemVariation `json:"variations,omitempty"`
+}
+
+// OrderItemVariation is a variation selected for a cart line, sent to Syrve as a modifier
+type OrderItemVariation struct {
+	GroupID    uuid.UUID `json:"group_id"`
+	GroupName  string    `json:"group_name"`
+	ExternalID string    `json:"external_id"`
+	Name       string    `json:"name"`
+	Quantity   int       `json:"quantity"`
+	Price      float64   `json:"price"`
+}
+
+// OrderCancelEvent is sent by order-service when an order already sent to Syrve is cancelled
+type OrderCancelEvent struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Reason  string    `json:"reason,omitempty"`
 }
@@ -43,7 +43,7 @@ func main() {
 	defer producer.Close()
 
 	// Initialize Bot Handler
-	botHandler := bot.NewHandler(tgClient, producer, cfg.ChatID, cfg.SyrveServiceURL, cfg.ProductServiceURL, cfg.WebServiceURL)
+	botHandler := bot.NewHandler(tgClient, producer, cfg.ChatID, cfg.SyrveServiceURL, cfg.ProductServiceURL, cfg.WebServiceURL, cfg.OrderServiceURL)
 
 	// Start Healthcheck Server
 	app := fiber.New()
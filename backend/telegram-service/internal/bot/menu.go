@@ -4,7 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonysanin/brobar/pkg/helpers"
 )
 
 type Product struct {
@@ -53,6 +59,13 @@ func (h *Handler) getMenuState(chatID int64) (string, interface{}, error) {
 		stateIcon = "🔴"
 		statusText = "ЗАКРИТО"
 		salesBtnText = "▶️ Відновити продажі"
+
+		if until, ok := settings["sales_paused_until"].(string); ok && until != "" {
+			statusText += fmt.Sprintf(" до %s", until)
+		}
+		if reason, ok := settings["sales_paused_reason"].(string); ok && reason != "" {
+			statusText += fmt.Sprintf(" (%s)", html.EscapeString(reason))
+		}
 	}
 
 	text := fmt.Sprintf("<b>Меню адміністратора</b>\nСтатус: %s %s\n\nОберіть дію:", stateIcon, statusText)
@@ -107,26 +120,15 @@ func (h *Handler) handleToggleSalesPaused(chatID int64, messageID int) error {
 	}
 
 	// Update
-	payload := map[string]string{
-		"value": newVal,
-		"type":  "boolean",
-	}
-	payloadBytes, _ := json.Marshal(payload)
-
-	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/settings/sales_paused", h.webURL), bytes.NewBuffer(payloadBytes))
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+	if err := h.updateSetting("sales_paused", newVal, "boolean"); err != nil {
 		h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка оновлення: %v", err), nil)
 		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка API: статус %d", resp.StatusCode), nil)
-		return nil
+	// Reason and resume time only make sense for the current pause
+	if newVal == "false" {
+		_ = h.updateSetting("sales_paused_reason", "", "string")
+		_ = h.updateSetting("sales_paused_until", "", "string")
 	}
 	
 	// Re-render menu by editing the message
@@ -144,6 +146,62 @@ func (h *Handler) handleToggleSalesPaused(chatID int64, messageID int) error {
 	return h.client.EditMessageText(chatID, messageID, text, keyboard)
 }
 
+// handlePauseCommand handles "/pause <minutes> [reason]" and pauses sales until the given time
+func (h *Handler) handlePauseCommand(chatID int64, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return h.client.SendMessage(chatID, "Використання: /pause &lt;хвилини&gt; [причина]", nil)
+	}
+
+	minutes, err := strconv.Atoi(fields[0])
+	if err != nil || minutes <= 0 {
+		return h.client.SendMessage(chatID, "❌ Вкажіть кількість хвилин, наприклад: /pause 30 Велике навантаження", nil)
+	}
+	reason := strings.Join(fields[1:], " ")
+
+	loc, err := time.LoadLocation(helpers.GetEnv("APP_TIMEZONE", "Europe/Kyiv"))
+	if err != nil {
+		loc = time.FixedZone("EET", 2*60*60)
+	}
+	until := time.Now().In(loc).Add(time.Duration(minutes) * time.Minute).Format("2006-01-02 15:04")
+
+	if err := h.updateSetting("sales_paused_reason", reason, "string"); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка оновлення: %v", err), nil)
+	}
+	if err := h.updateSetting("sales_paused_until", until, "string"); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка оновлення: %v", err), nil)
+	}
+	if err := h.updateSetting("sales_paused", "true", "boolean"); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка оновлення: %v", err), nil)
+	}
+
+	return h.handleMenu(chatID)
+}
+
+func (h *Handler) updateSetting(key, value, settingType string) error {
+	payload := map[string]string{
+		"value": value,
+		"type":  settingType,
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/settings/%s", h.webURL, key), bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("статус %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (h *Handler) handleCompareStopList(chatID int64) error {
 	payload := fmt.Sprintf(`{"chat_id": %d, "initiator": "manual"}`, chatID)
 	if err := h.producer.SendMessage("syrve.sync.start", payload); err != nil {
@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// handleReleaseOrder sends a held pre-order to the kitchen early, order-service
+// posts the confirmation to the chat itself
+func (h *Handler) handleReleaseOrder(chatID int64, orderID string) error {
+	if err := h.releaseOrder(orderID); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Не вдалося передати замовлення на кухню: %v", err), nil)
+	}
+	return nil
+}
+
+func (h *Handler) releaseOrder(orderID string) error {
+	resp, err := http.Post(fmt.Sprintf("%s/orders/%s/release", h.orderURL, orderID), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			return fmt.Errorf("%s", body.Error)
+		}
+		return fmt.Errorf("статус %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	riskOnlineOnly = "online_only"
+	riskBlock      = "block"
+)
+
+// handleFlagPhoneCallback flags the phone of an order from the buttons of its notification
+func (h *Handler) handleFlagPhoneCallback(chatID int64, orderID, action, flaggedBy string) error {
+	phone, err := h.orderPhone(orderID)
+	if err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Не вдалося отримати замовлення: %v", err), nil)
+	}
+
+	reason := fmt.Sprintf("Замовлення #%s", strings.ToUpper(orderID[:min(8, len(orderID))]))
+	return h.flagPhone(chatID, phone, action, reason, flaggedBy)
+}
+
+// handleFlagCommand handles "/flag <phone> [block] [reason]", without "block" the phone must pay online
+func (h *Handler) handleFlagCommand(chatID int64, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return h.client.SendMessage(chatID, "Використання: /flag &lt;телефон&gt; [block] [причина]", nil)
+	}
+
+	phone, rest := fields[0], fields[1:]
+	action := riskOnlineOnly
+	if len(rest) > 0 && strings.EqualFold(rest[0], riskBlock) {
+		action = riskBlock
+		rest = rest[1:]
+	}
+
+	return h.flagPhone(chatID, phone, action, strings.Join(rest, " "), "telegram")
+}
+
+// handleUnflagCommand handles "/unflag <phone>"
+func (h *Handler) handleUnflagCommand(chatID int64, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return h.client.SendMessage(chatID, "Використання: /unflag &lt;телефон&gt;", nil)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/risk/phones/%s", h.orderURL, url.PathEscape(fields[0])), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка: %v", err), nil)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return h.client.SendMessage(chatID, "Номер не позначено", nil)
+	}
+	if err := orderServiceError(resp); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка: %v", err), nil)
+	}
+
+	return h.client.SendMessage(chatID, fmt.Sprintf("✅ Позначку з номера %s знято", html.EscapeString(fields[0])), nil)
+}
+
+func (h *Handler) flagPhone(chatID int64, phone, action, reason, flaggedBy string) error {
+	payload, _ := json.Marshal(map[string]string{
+		"action":     action,
+		"reason":     reason,
+		"flagged_by": flaggedBy,
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/risk/phones/%s", h.orderURL, url.PathEscape(phone)), bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Помилка: %v", err), nil)
+	}
+	defer resp.Body.Close()
+
+	if err := orderServiceError(resp); err != nil {
+		return h.client.SendMessage(chatID, fmt.Sprintf("❌ Не вдалося позначити номер: %v", err), nil)
+	}
+
+	label := "🚩 тепер платить лише онлайн"
+	if action == riskBlock {
+		label = "⛔ заблоковано"
+	}
+	return h.client.SendMessage(chatID, fmt.Sprintf("Номер %s %s", html.EscapeString(phone), label), nil)
+}
+
+func (h *Handler) orderPhone(orderID string) (string, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/orders/%s", h.orderURL, url.PathEscape(orderID)))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := orderServiceError(resp); err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Data struct {
+			Phone string `json:"phone"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Data.Phone == "" {
+		return "", fmt.Errorf("у замовленні немає телефону")
+	}
+
+	return body.Data.Phone, nil
+}
+
+// orderServiceError turns a failed order-service response into an error
+func orderServiceError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+		return fmt.Errorf("%s", body.Error)
+	}
+	return fmt.Errorf("статус %d", resp.StatusCode)
+}
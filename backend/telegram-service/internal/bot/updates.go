@@ -2,8 +2,10 @@ package bot
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"strings"
+	"unicode"
 
 	"github.com/tonysanin/brobar/pkg/rabbitmq"
 	"github.com/tonysanin/brobar/pkg/telegram"
@@ -43,9 +45,10 @@ type Handler struct {
 	syrveURL      string
 	productURL    string
 	webURL        string
+	orderURL      string
 }
 
-func NewHandler(client *telegram.Client, producer *rabbitmq.Producer, allowedChatID int64, syrveURL, productURL, webURL string) *Handler {
+func NewHandler(client *telegram.Client, producer *rabbitmq.Producer, allowedChatID int64, syrveURL, productURL, webURL, orderURL string) *Handler {
 	return &Handler{
 		client:        client,
 		producer:      producer,
@@ -53,6 +56,7 @@ func NewHandler(client *telegram.Client, producer *rabbitmq.Producer, allowedCha
 		syrveURL:      syrveURL,
 		productURL:    productURL,
 		webURL:        webURL,
+		orderURL:      orderURL,
 	}
 }
 
@@ -75,11 +79,47 @@ func (h *Handler) HandleUpdate(body []byte) error {
 				return h.handleMenu(update.Message.Chat.ID)
 			}
 		}
+
+		command, args := splitCommand(update.Message.Text)
+		if command == "/pause" {
+			if update.Message.Chat.ID == h.allowedChatID {
+				return h.handlePauseCommand(update.Message.Chat.ID, args)
+			}
+		}
+
+		if command == "/flag" {
+			if update.Message.Chat.ID == h.allowedChatID {
+				return h.handleFlagCommand(update.Message.Chat.ID, args)
+			}
+		}
+
+		if command == "/unflag" {
+			if update.Message.Chat.ID == h.allowedChatID {
+				return h.handleUnflagCommand(update.Message.Chat.ID, args)
+			}
+		}
 	}
 
 	return nil
 }
 
+// splitCommand splits "/command@botname args" into the lowercase command without the bot
+// name and its arguments. Text that is not a command gives an empty command.
+func splitCommand(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", ""
+	}
+
+	command, args := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		command, args = text[:i], text[i+1:]
+	}
+	command, _, _ = strings.Cut(command, "@")
+
+	return strings.ToLower(command), strings.TrimSpace(args)
+}
+
 func (h *Handler) handleCallbackQuery(cq *CallbackQuery) error {
 	log.Printf("Received callback: %s from %d", cq.Data, cq.From.ID)
 
@@ -133,6 +173,18 @@ func (h *Handler) handleCallbackQuery(cq *CallbackQuery) error {
 			h.handleToggleSalesPaused(cq.Message.Chat.ID, cq.Message.MessageID)
 		}
 		h.client.AnswerCallbackQuery(cq.ID, "Оновлюємо...")
+	} else if action == "release_order" {
+		if len(parts) < 2 {
+			return nil
+		}
+		h.client.AnswerCallbackQuery(cq.ID, "Передаємо на кухню...")
+		h.handleReleaseOrder(cq.Message.Chat.ID, parts[1])
+	} else if action == "flag_phone" {
+		if len(parts) < 3 {
+			return nil
+		}
+		h.client.AnswerCallbackQuery(cq.ID, "Позначаємо номер...")
+		h.handleFlagPhoneCallback(cq.Message.Chat.ID, parts[1], parts[2], flaggedBy(cq.From))
 	} else if action == "show_stock" {
 		h.handleShowStock(cq.Message.Chat.ID)
 		h.client.AnswerCallbackQuery(cq.ID, "Формуємо...")
@@ -161,3 +213,11 @@ func (h *Handler) publishTaxiConfirm(chatID int64, orderID string) {
 	msg, _ := json.Marshal(payload)
 	_ = h.producer.SendMessage("taxi_confirms", string(msg))
 }
+
+// flaggedBy names the chat member who pressed a button
+func flaggedBy(user User) string {
+	if user.Username != "" {
+		return "telegram:@" + user.Username
+	}
+	return fmt.Sprintf("telegram:%d", user.ID)
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		text, command, args string
+	}{
+		{"/pause 30 Велике навантаження", "/pause", "30 Велике навантаження"},
+		{"/pause@brobar_bot 30", "/pause", "30"},
+		{"/PAUSE", "/pause", ""},
+		{"/pause\n30", "/pause", "30"},
+		{"  /flag +380501234567 fraud ", "/flag", "+380501234567 fraud"},
+		{"/pauseall 30", "/pauseall", "30"},
+		{"/flagged", "/flagged", ""},
+		{"pause 30", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			command, args := splitCommand(tt.text)
+			assert.Equal(t, tt.command, command)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
@@ -14,6 +14,7 @@ type Config struct {
 	SyrveServiceURL   string
 	ProductServiceURL string
 	WebServiceURL     string
+	OrderServiceURL   string
 }
 
 func NewConfig() *Config {
@@ -28,5 +29,6 @@ func NewConfig() *Config {
 		SyrveServiceURL:   helpers.GetEnv("SYRVE_SERVICE_URL", "http://syrve-service:3004"),
 		ProductServiceURL: helpers.GetEnv("PRODUCT_SERVICE_URL", "http://product-service:3000"),
 		WebServiceURL:     helpers.GetEnv("WEB_SERVICE_URL", "http://web-service:3006"),
+		OrderServiceURL:   helpers.GetEnv("ORDER_SERVICE_URL", "http://order-service:3001"),
 	}
 }
@@ -39,8 +39,10 @@ func main() {
 
 	userRepository := repositories.NewUserRepository(db)
 	userService := services.NewUserService(userRepository)
+	loyaltyRepository := repositories.NewLoyaltyRepository(db)
+	loyaltyService := services.NewLoyaltyService(db, loyaltyRepository, userRepository)
 
-	server := api.NewServer(userService)
+	server := api.NewServer(userService, loyaltyService)
 
 	log.Printf("Starting server on :%s", cfg.Port)
 	if err := server.Listen(":" + cfg.Port); err != nil {
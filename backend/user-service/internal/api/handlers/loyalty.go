@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/response"
+	"github.com/tonysanin/brobar/user-service/internal/api/requests"
+	customerrors "github.com/tonysanin/brobar/user-service/internal/errors"
+	"github.com/tonysanin/brobar/user-service/internal/services"
+)
+
+const (
+	defaultLoyaltyHistory = 50
+	maxLoyaltyHistory     = 200
+)
+
+type LoyaltyHandler struct {
+	service   *services.LoyaltyService
+	jwtSecret []byte
+}
+
+func NewLoyaltyHandler(s *services.LoyaltyService, jwtSecret []byte) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		service:   s,
+		jwtSecret: jwtSecret,
+	}
+}
+
+// GetMyLoyalty returns the promo card, points balance and history of the signed-in user
+func (h *LoyaltyHandler) GetMyLoyalty(c fiber.Ctx) error {
+	userID, ok := tokenUserID(c, h.jwtSecret)
+	if !ok {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultLoyaltyHistory)))
+	if err != nil || limit < 1 || limit > maxLoyaltyHistory {
+		return response.BadRequest(c, errors.New("invalid limit"))
+	}
+
+	account, err := h.service.GetAccount(c.Context(), userID, limit)
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+
+	return response.Success(c, account)
+}
+
+// Earn credits the points of a completed order
+func (h *LoyaltyHandler) Earn(c fiber.Ctx) error {
+	userID, req, err := h.pointsRequest(c)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := h.service.Earn(c.Context(), userID, req.OrderID, req.Points); err != nil {
+		return loyaltyError(c, err)
+	}
+
+	return response.Success(c, nil)
+}
+
+// Redeem debits the points paid for an order
+func (h *LoyaltyHandler) Redeem(c fiber.Ctx) error {
+	userID, req, err := h.pointsRequest(c)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := h.service.Redeem(c.Context(), userID, req.OrderID, req.Points); err != nil {
+		return loyaltyError(c, err)
+	}
+
+	return response.Success(c, nil)
+}
+
+// Reverse undoes the points of a cancelled order
+func (h *LoyaltyHandler) Reverse(c fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return response.BadRequest(c, errors.New("invalid user ID"))
+	}
+
+	var req requests.LoyaltyReverseRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return response.BadRequest(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	if err := h.service.Reverse(c.Context(), userID, req.OrderID); err != nil {
+		return loyaltyError(c, err)
+	}
+
+	return response.Success(c, nil)
+}
+
+func (h *LoyaltyHandler) pointsRequest(c fiber.Ctx) (uuid.UUID, *requests.LoyaltyPointsRequest, error) {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return uuid.Nil, nil, errors.New("invalid user ID")
+	}
+
+	var req requests.LoyaltyPointsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return uuid.Nil, nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	return userID, &req, nil
+}
+
+func loyaltyError(c fiber.Ctx, err error) error {
+	if errors.Is(err, customerrors.UserNotFound) {
+		return response.Error(c, fiber.StatusNotFound, err)
+	}
+	if errors.Is(err, customerrors.LoyaltyInsufficientPoints) {
+		return response.ErrorWithCode(c, fiber.StatusConflict, "insufficient_points", err)
+	}
+	if errors.Is(err, customerrors.LoyaltyInvalidPoints) {
+		return response.BadRequest(c, err)
+	}
+	return response.Error(c, fiber.StatusInternalServerError, err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/tonysanin/brobar/pkg/response"
+	"github.com/tonysanin/brobar/pkg/validator"
 	"github.com/tonysanin/brobar/user-service/internal/api/requests"
 	customerrors "github.com/tonysanin/brobar/user-service/internal/errors"
 	"github.com/tonysanin/brobar/user-service/internal/models"
@@ -22,16 +23,21 @@ func (h *UserHandler) Register(c fiber.Ctx) error {
 		return response.BadRequest(c, err)
 	}
 
+	phone, err := validator.NormalizePhone(req.Phone)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
 	user := models.User{
 		Name:      req.Name,
 		Email:     req.Email,
-		Phone:     stringPtr(req.Phone),
+		Phone:     stringPtr(phone),
 		Password:  req.Password,
 		PromoCard: stringPtr(req.PromoCard),
 		Address:   stringPtr(req.Address),
 	}
 
-	err := h.service.CreateUser(c.Context(), &user)
+	err = h.service.CreateUser(c.Context(), &user)
 	if err != nil {
 		if errors.Is(err, customerrors.UserAlreadyExists) {
 			return response.Error(c, fiber.StatusConflict, err)
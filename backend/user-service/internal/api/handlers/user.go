@@ -40,46 +40,56 @@ func (h *UserHandler) GetUserByID(c fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserMe(c fiber.Ctx) error {
+	userID, ok := tokenUserID(c, h.jwtSecret)
+	if !ok {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+	}
+
+	user, err := h.service.GetUserById(c.Context(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err)
+	}
+
+	return response.Success(c, user.ToDTO())
+}
+
+// tokenUserID returns the user of the bearer token sent with the request
+func tokenUserID(c fiber.Ctx, jwtSecret []byte) (uuid.UUID, bool) {
 	authHeader := c.Get("Authorization")
 
 	if authHeader == "" {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+		return uuid.Nil, false
 	}
 
 	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenStr == authHeader {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+		return uuid.Nil, false
 	}
 
 	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method")
 		}
-		return h.jwtSecret, nil
+		return jwtSecret, nil
 	})
 	if err != nil || !token.Valid {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+		return uuid.Nil, false
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+		return uuid.Nil, false
 	}
 
 	userIDStr, ok := claims["user_id"].(string)
 	if !ok {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
+		return uuid.Nil, false
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return response.Error(c, fiber.StatusUnauthorized, fiber.ErrUnauthorized)
-	}
-
-	user, err := h.service.GetUserById(c.Context(), userID)
-	if err != nil {
-		return response.Error(c, fiber.StatusNotFound, err)
+		return uuid.Nil, false
 	}
 
-	return response.Success(c, user.ToDTO())
+	return userID, true
 }
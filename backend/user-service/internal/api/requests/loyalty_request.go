@@ -0,0 +1,31 @@
+package requests
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/pkg/validator"
+)
+
+// LoyaltyPointsRequest - points earned or redeemed by an order, sent by order-service
+type LoyaltyPointsRequest struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Points  int       `json:"points"`
+}
+
+func (r LoyaltyPointsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.OrderID, validation.Required, validator.IsUUID),
+		validation.Field(&r.Points, validation.Required, validation.Min(1)),
+	)
+}
+
+// LoyaltyReverseRequest - order whose points are reversed
+type LoyaltyReverseRequest struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+func (r LoyaltyReverseRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.OrderID, validation.Required, validator.IsUUID),
+	)
+}
@@ -10,13 +10,15 @@ import (
 )
 
 type Server struct {
-	app         *fiber.App
-	userService *services.UserService
-	userHandler *handlers.UserHandler
+	app            *fiber.App
+	userService    *services.UserService
+	userHandler    *handlers.UserHandler
+	loyaltyHandler *handlers.LoyaltyHandler
 }
 
 func NewServer(
 	userService *services.UserService,
+	loyaltyService *services.LoyaltyService,
 ) *Server {
 	s := &Server{
 		app: fiber.New(fiber.Config{
@@ -32,6 +34,7 @@ func NewServer(
 	jwtSecret := []byte(helpers.GetEnv("JWT_SECRET", ""))
 
 	s.userHandler = handlers.NewUserHandler(userService, jwtSecret)
+	s.loyaltyHandler = handlers.NewLoyaltyHandler(loyaltyService, jwtSecret)
 
 	s.SetupRoutes()
 
@@ -53,7 +56,14 @@ func (s *Server) SetupRoutes() {
 
 	user := s.app.Group("/user")
 	user.Get("/me", s.userHandler.GetUserMe)
+	user.Get("/me/loyalty", s.loyaltyHandler.GetMyLoyalty)
 	user.Get("/:id", s.userHandler.GetUserByID)
+
+	// Ledger operations of order-service, not exposed through the gateway
+	loyalty := s.app.Group("/loyalty")
+	loyalty.Post("/:user_id/earn", s.loyaltyHandler.Earn)
+	loyalty.Post("/:user_id/redeem", s.loyaltyHandler.Redeem)
+	loyalty.Post("/:user_id/reverse", s.loyaltyHandler.Reverse)
 }
 
 func (s *Server) Listen(address string) error {
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoyaltyDTO struct {
+	PromoCard string                  `json:"promo_card"`
+	Balance   int                     `json:"balance"`
+	History   []LoyaltyTransactionDTO `json:"history"`
+}
+
+type LoyaltyTransactionDTO struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	Type      string    `json:"type"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	LoyaltyInsufficientPoints = errors.New("insufficient loyalty points")
+	LoyaltyInvalidPoints      = errors.New("points must be positive")
+)
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tonysanin/brobar/user-service/internal/dto"
+)
+
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyEarn    LoyaltyTransactionType = "earn"
+	LoyaltyRedeem  LoyaltyTransactionType = "redeem"
+	LoyaltyReverse LoyaltyTransactionType = "reverse"
+)
+
+// LoyaltyTransaction is an entry of the points ledger, redemptions have negative points
+type LoyaltyTransaction struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	UserID    uuid.UUID              `json:"-" db:"user_id"`
+	OrderID   uuid.UUID              `json:"order_id" db:"order_id"`
+	Type      LoyaltyTransactionType `json:"type" db:"type"`
+	Points    int                    `json:"points" db:"points"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+func (t *LoyaltyTransaction) ToDTO() dto.LoyaltyTransactionDTO {
+	return dto.LoyaltyTransactionDTO{
+		OrderID:   t.OrderID,
+		Type:      string(t.Type),
+		Points:    t.Points,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	customerrors "github.com/tonysanin/brobar/user-service/internal/errors"
+	"github.com/tonysanin/brobar/user-service/internal/models"
+)
+
+type LoyaltyRepository struct {
+	db dbExecutor
+}
+
+func NewLoyaltyRepository(db *sqlx.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+func (r *LoyaltyRepository) WithTx(tx *sqlx.Tx) *LoyaltyRepository {
+	return &LoyaltyRepository{db: tx}
+}
+
+// LockUser serializes ledger changes of the user until the transaction ends, so it must run within WithTx
+func (r *LoyaltyRepository) LockUser(ctx context.Context, userID uuid.UUID) error {
+	const query = `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+	var id uuid.UUID
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	err := r.db.GetContext(ctx, &id, query, userID)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return fmt.Errorf("database query timed out")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerrors.UserNotFound
+		}
+		log.Printf("failed to lock user: %v", err)
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LoyaltyRepository) GetBalance(ctx context.Context, userID uuid.UUID) (int, error) {
+	const query = `SELECT COALESCE(SUM(points), 0) FROM loyalty_transactions WHERE user_id = $1`
+	var balance int
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.GetContext(ctx, &balance, query, userID); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return 0, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get loyalty balance: %v", err)
+		return 0, fmt.Errorf("failed to get loyalty balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// GetOrderPoints sums the points the order has earned and redeemed for the user
+func (r *LoyaltyRepository) GetOrderPoints(ctx context.Context, userID, orderID uuid.UUID) (int, error) {
+	const query = `SELECT COALESCE(SUM(points), 0) FROM loyalty_transactions WHERE user_id = $1 AND order_id = $2`
+	var points int
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.GetContext(ctx, &points, query, userID, orderID); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return 0, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get order loyalty points: %v", err)
+		return 0, fmt.Errorf("failed to get order loyalty points: %w", err)
+	}
+
+	return points, nil
+}
+
+// CreateTransaction adds the entry unless the order already has one of the same type.
+// Returns false when nothing was written.
+func (r *LoyaltyRepository) CreateTransaction(ctx context.Context, transaction *models.LoyaltyTransaction) (bool, error) {
+	const query = `
+		INSERT INTO loyalty_transactions (id, user_id, order_id, type, points, created_at)
+		VALUES (:id, :user_id, :order_id, :type, :points, :created_at)
+		ON CONFLICT (order_id, type) DO NOTHING`
+
+	if transaction.ID == uuid.Nil {
+		transaction.ID = uuid.New()
+	}
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.NamedExecContext(ctx, query, transaction)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return false, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to create loyalty transaction: %v", err)
+		return false, fmt.Errorf("failed to create loyalty transaction: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetTransactions returns the latest ledger entries of the user, newest first
+func (r *LoyaltyRepository) GetTransactions(ctx context.Context, userID uuid.UUID, limit int) ([]models.LoyaltyTransaction, error) {
+	const query = `
+		SELECT * FROM loyalty_transactions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+	var transactions []models.LoyaltyTransaction
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.SelectContext(ctx, &transactions, query, userID, limit); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return nil, fmt.Errorf("database query timed out")
+		}
+		log.Printf("failed to get loyalty transactions: %v", err)
+		return nil, fmt.Errorf("failed to get loyalty transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// IssuePromoCard gives the user a promo card number when they have none and returns the user's card
+func (r *LoyaltyRepository) IssuePromoCard(ctx context.Context, userID uuid.UUID) (string, error) {
+	const query = `
+		UPDATE users
+		SET promo_card = COALESCE(NULLIF(promo_card, ''), 'BRO' || LPAD(nextval('loyalty_card_seq')::text, 8, '0'))
+		WHERE id = $1
+		RETURNING promo_card`
+	var promoCard string
+
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	if err := r.db.GetContext(ctx, &promoCard, query, userID); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Printf("database query timed out")
+			return "", fmt.Errorf("database query timed out")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customerrors.UserNotFound
+		}
+		log.Printf("failed to issue promo card: %v", err)
+		return "", fmt.Errorf("failed to issue promo card: %w", err)
+	}
+
+	return promoCard, nil
+}
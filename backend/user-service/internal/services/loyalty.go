@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/tonysanin/brobar/user-service/internal/dto"
+	customerrors "github.com/tonysanin/brobar/user-service/internal/errors"
+	"github.com/tonysanin/brobar/user-service/internal/models"
+	"github.com/tonysanin/brobar/user-service/internal/repositories"
+)
+
+// LoyaltyService keeps the points ledger. Rates and caps are decided by order-service,
+// the ledger only guards the balance and makes every order operation happen once.
+type LoyaltyService struct {
+	db       *sqlx.DB
+	repo     *repositories.LoyaltyRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewLoyaltyService(db *sqlx.DB, repo *repositories.LoyaltyRepository, userRepo *repositories.UserRepository) *LoyaltyService {
+	return &LoyaltyService{db: db, repo: repo, userRepo: userRepo}
+}
+
+// Earn credits the points for a completed order, the user gets a promo card on first earning
+func (s *LoyaltyService) Earn(ctx context.Context, userID, orderID uuid.UUID, points int) error {
+	if points <= 0 {
+		return customerrors.LoyaltyInvalidPoints
+	}
+
+	return s.inTx(ctx, userID, func(repo *repositories.LoyaltyRepository) error {
+		if _, err := repo.IssuePromoCard(ctx, userID); err != nil {
+			return err
+		}
+
+		_, err := repo.CreateTransaction(ctx, &models.LoyaltyTransaction{
+			UserID:  userID,
+			OrderID: orderID,
+			Type:    models.LoyaltyEarn,
+			Points:  points,
+		})
+		return err
+	})
+}
+
+// Redeem debits the points paid for an order, the balance must cover them
+func (s *LoyaltyService) Redeem(ctx context.Context, userID, orderID uuid.UUID, points int) error {
+	if points <= 0 {
+		return customerrors.LoyaltyInvalidPoints
+	}
+
+	return s.inTx(ctx, userID, func(repo *repositories.LoyaltyRepository) error {
+		balance, err := repo.GetBalance(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if balance < points {
+			return fmt.Errorf("%w (available: %d)", customerrors.LoyaltyInsufficientPoints, balance)
+		}
+
+		_, err = repo.CreateTransaction(ctx, &models.LoyaltyTransaction{
+			UserID:  userID,
+			OrderID: orderID,
+			Type:    models.LoyaltyRedeem,
+			Points:  -points,
+		})
+		return err
+	})
+}
+
+// Reverse undoes everything the order did to the balance: redeemed points come back
+// and earned ones are taken away. Reversing twice changes nothing.
+func (s *LoyaltyService) Reverse(ctx context.Context, userID, orderID uuid.UUID) error {
+	return s.inTx(ctx, userID, func(repo *repositories.LoyaltyRepository) error {
+		points, err := repo.GetOrderPoints(ctx, userID, orderID)
+		if err != nil {
+			return err
+		}
+		if points == 0 {
+			return nil
+		}
+
+		_, err = repo.CreateTransaction(ctx, &models.LoyaltyTransaction{
+			UserID:  userID,
+			OrderID: orderID,
+			Type:    models.LoyaltyReverse,
+			Points:  -points,
+		})
+		return err
+	})
+}
+
+// GetAccount returns the promo card, balance and latest transactions of the user
+func (s *LoyaltyService) GetAccount(ctx context.Context, userID uuid.UUID, limit int) (*dto.LoyaltyDTO, error) {
+	user, err := s.userRepo.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.repo.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.repo.GetTransactions(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &dto.LoyaltyDTO{
+		PromoCard: user.ToDTO().PromoCard,
+		Balance:   balance,
+		History:   make([]dto.LoyaltyTransactionDTO, len(transactions)),
+	}
+	for i := range transactions {
+		account.History[i] = transactions[i].ToDTO()
+	}
+
+	return account, nil
+}
+
+// inTx runs fn in a transaction holding the user's ledger lock
+func (s *LoyaltyService) inTx(ctx context.Context, userID uuid.UUID, fn func(repo *repositories.LoyaltyRepository) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	repo := s.repo.WithTx(tx)
+	if err := repo.LockUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := fn(repo); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+DROP SEQUENCE IF EXISTS loyalty_card_seq;
+DROP TABLE IF EXISTS loyalty_transactions;
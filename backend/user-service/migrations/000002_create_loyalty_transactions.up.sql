@@ -0,0 +1,16 @@
+-- Loyalty ledger: the balance of a user is the sum of their transactions
+CREATE TABLE loyalty_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('earn', 'redeem', 'reverse')),
+    points INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- an order earns, redeems and is reversed at most once
+    UNIQUE (order_id, type)
+);
+
+CREATE INDEX idx_loyalty_transactions_user_id ON loyalty_transactions(user_id, created_at DESC);
+
+-- Numbers of promo cards issued to users who did not have one
+CREATE SEQUENCE loyalty_card_seq;
@@ -38,7 +38,7 @@ func main() {
 	}
 
 	settingRepo := repositories.NewSettingRepository(db)
-	settingService := services.NewSettingService(settingRepo)
+	settingService := services.NewSettingService(settingRepo, cfg.AppTimezone)
 
 	reviewRepo := repositories.NewReviewRepository(db)
 	reviewService := services.NewReviewService(reviewRepo)
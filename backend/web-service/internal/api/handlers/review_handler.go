@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/tonysanin/brobar/pkg/response"
+	"github.com/tonysanin/brobar/pkg/validator"
 	"github.com/tonysanin/brobar/web-service/internal/models"
 	"github.com/tonysanin/brobar/web-service/internal/services"
 )
@@ -38,6 +39,13 @@ func (h *ReviewHandler) CreateReview(c fiber.Ctx) error {
 	if req.ServiceRating < 1 || req.ServiceRating > 5 {
 		return response.Error(c, fiber.StatusBadRequest, errors.New("service_rating must be between 1 and 5"))
 	}
+	if req.Phone != nil && *req.Phone != "" {
+		phone, err := validator.NormalizePhone(*req.Phone)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, err)
+		}
+		req.Phone = &phone
+	}
 
 	review := &models.Review{
 		FoodRating:    req.FoodRating,
@@ -38,6 +38,9 @@ func (h *SettingHandler) UpdateSetting(c fiber.Ctx) error {
 	}
 
 	if err := h.service.UpdateSetting(key, req.Value, req.Type); err != nil {
+		if errors.Is(err, services.ErrInvalidSetting) {
+			return response.Error(c, fiber.StatusBadRequest, err)
+		}
 		return response.Error(c, fiber.StatusInternalServerError, errors.New("failed to update setting"))
 	}
 
@@ -0,0 +1,28 @@
+package models
+
+import "encoding/json"
+
+// DeliveryZone is an item of the "delivery_zones" setting: a GeoJSON Polygon/MultiPolygon
+// geometry or, for older zones, a ring of Radius/InnerRadius km around the zone center.
+// Overlapping zones are resolved by Priority.
+type DeliveryZone struct {
+	Name           string    `json:"name"`
+	Price          float64   `json:"price"`
+	FreeOrderPrice float64   `json:"freeOrderPrice"`
+	Radius         float64   `json:"radius"`
+	InnerRadius    float64   `json:"innerRadius"`
+	Geometry       *Geometry `json:"geometry,omitempty"`
+	Priority       int       `json:"priority,omitempty"`
+
+	// Zone rules, zero values fall back to the general ones
+	MinOrderAmount float64  `json:"minOrderAmount,omitempty"`
+	DoorPrice      *float64 `json:"doorPrice,omitempty"`
+	PaymentMethods []string `json:"paymentMethods,omitempty"` // "online", "cash"
+	PrepMinutes    int      `json:"prepMinutes,omitempty"`
+}
+
+// Geometry is a GeoJSON geometry, positions are [lng, lat]
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// LoyaltySettings is the "loyalty" setting. One point is worth one hryvnia.
+type LoyaltySettings struct {
+	Enabled          bool    `json:"enabled"`
+	EarnRate         float64 `json:"earn_rate"`          // points per hryvnia paid for the items
+	MaxRedeemPercent float64 `json:"max_redeem_percent"` // share of the discounted items total
+}
@@ -0,0 +1,22 @@
+package models
+
+// DaySchedule is a shift of a weekday, an end not after the start means the shift ends the next day
+type DaySchedule struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Closed bool   `json:"closed"`
+}
+
+// WorkingHours is the "working_hours" setting
+type WorkingHours struct {
+	Delivery map[string]DaySchedule `json:"delivery"`
+	Pickup   map[string]DaySchedule `json:"pickup"`
+}
+
+// ScheduleOverride replaces the weekday hours on a date (YYYY-MM-DD), an item
+// of the "working_hours_overrides" setting
+type ScheduleOverride struct {
+	Date string `json:"date"`
+	DaySchedule
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tonysanin/brobar/web-service/internal/models"
+)
+
+func validateDeliveryZones(value string) error {
+	var zones []models.DeliveryZone
+	if err := json.Unmarshal([]byte(value), &zones); err != nil {
+		return fmt.Errorf("invalid delivery zones: %w", err)
+	}
+
+	for i, zone := range zones {
+		if zone.Name == "" {
+			return fmt.Errorf("zone %d: name is required", i+1)
+		}
+		if zone.Price < 0 || zone.FreeOrderPrice < 0 || zone.MinOrderAmount < 0 || (zone.DoorPrice != nil && *zone.DoorPrice < 0) {
+			return fmt.Errorf("zone %s: prices must not be negative", zone.Name)
+		}
+		if zone.PrepMinutes < 0 {
+			return fmt.Errorf("zone %s: prepMinutes must not be negative", zone.Name)
+		}
+		for _, method := range zone.PaymentMethods {
+			if method != "online" && method != "cash" {
+				return fmt.Errorf("zone %s: unknown payment method %q", zone.Name, method)
+			}
+		}
+
+		if zone.Geometry == nil {
+			if zone.Radius <= zone.InnerRadius || zone.InnerRadius < 0 {
+				return fmt.Errorf("zone %s: radius must be greater than innerRadius", zone.Name)
+			}
+			continue
+		}
+
+		if err := validateGeometry(zone.Geometry); err != nil {
+			return fmt.Errorf("zone %s: %w", zone.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateGeometry(geometry *models.Geometry) error {
+	var polygons [][][][]float64
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		polygons = append(polygons, polygon)
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+	default:
+		return fmt.Errorf("geometry must be a Polygon or MultiPolygon")
+	}
+
+	if len(polygons) == 0 {
+		return fmt.Errorf("geometry has no polygons")
+	}
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return fmt.Errorf("polygon has no rings")
+		}
+		for _, ring := range polygon {
+			if len(ring) < 3 {
+				return fmt.Errorf("a polygon ring needs at least 3 positions")
+			}
+			for _, position := range ring {
+				if len(position) < 2 || position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+					return fmt.Errorf("positions must be [lng, lat]")
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tonysanin/brobar/web-service/internal/models"
+)
+
+func validateLoyalty(value string) error {
+	var settings models.LoyaltySettings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return fmt.Errorf("invalid loyalty settings: %w", err)
+	}
+
+	if settings.EarnRate < 0 || settings.EarnRate > 1 {
+		return fmt.Errorf("earn_rate must be between 0 and 1")
+	}
+	if settings.MaxRedeemPercent < 0 || settings.MaxRedeemPercent > 100 {
+		return fmt.Errorf("max_redeem_percent must be between 0 and 100")
+	}
+
+	return nil
+}
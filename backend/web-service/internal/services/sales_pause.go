@@ -0,0 +1,72 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/tonysanin/brobar/web-service/internal/models"
+)
+
+const salesPausedUntilLayout = "2006-01-02 15:04"
+
+// salesPauseKeys are the settings describing a sales pause
+var salesPauseKeys = map[string]bool{
+	"sales_paused":        true,
+	"sales_paused_reason": true,
+	"sales_paused_until":  true,
+}
+
+// hideExpiredPause shows a sales pause whose resume time has passed as lifted, so every
+// reader (the order checks and the Telegram menu) sees sales open again. Only the returned
+// settings change, the stored ones stay as they were.
+func (s *SettingService) hideExpiredPause(settings []models.Setting) {
+	var paused, until *models.Setting
+	for i := range settings {
+		switch settings[i].Key {
+		case "sales_paused":
+			paused = &settings[i]
+		case "sales_paused_until":
+			until = &settings[i]
+		}
+	}
+	if paused == nil || paused.Value != "true" || until == nil || until.Value == "" {
+		return
+	}
+
+	resumeAt, err := time.ParseInLocation(salesPausedUntilLayout, until.Value, s.location)
+	if err != nil {
+		log.Printf("failed to parse sales_paused_until %q: %v", until.Value, err)
+		return
+	}
+	if time.Now().Before(resumeAt) {
+		return
+	}
+
+	lifted := map[string]string{
+		"sales_paused":        "false",
+		"sales_paused_reason": "",
+		"sales_paused_until":  "",
+	}
+	for i := range settings {
+		if value, ok := lifted[settings[i].Key]; ok {
+			settings[i].Value = value
+		}
+	}
+}
+
+// clearExpiredResumeTime drops a resume time that has already passed, so a new pause without
+// one isn't shown as lifted right away
+func (s *SettingService) clearExpiredResumeTime() error {
+	until, err := s.repo.GetByKey("sales_paused_until")
+	if err != nil || until == nil || until.Value == "" {
+		return err
+	}
+
+	resumeAt, err := time.ParseInLocation(salesPausedUntilLayout, until.Value, s.location)
+	if err == nil && time.Now().Before(resumeAt) {
+		return nil
+	}
+
+	until.Value = ""
+	return s.repo.Update(until)
+}
@@ -1,27 +1,77 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/tonysanin/brobar/web-service/internal/models"
 	"github.com/tonysanin/brobar/web-service/internal/repositories"
 )
 
+var ErrInvalidSetting = errors.New("invalid setting value")
+
+// settingValidators check structured settings before they are saved
+var settingValidators = map[string]func(value string) error{
+	"working_hours":           validateWorkingHours,
+	"working_hours_overrides": validateWorkingHoursOverrides,
+	"delivery_zones":          validateDeliveryZones,
+	"loyalty":                 validateLoyalty,
+}
+
 type SettingService struct {
-	repo *repositories.SettingRepository
+	repo     *repositories.SettingRepository
+	location *time.Location
 }
 
-func NewSettingService(repo *repositories.SettingRepository) *SettingService {
-	return &SettingService{repo: repo}
+func NewSettingService(repo *repositories.SettingRepository, timezone string) *SettingService {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.FixedZone("EET", 2*60*60)
+	}
+	return &SettingService{repo: repo, location: loc}
 }
 
 func (s *SettingService) GetAllSettings() ([]models.Setting, error) {
-	return s.repo.GetAll()
+	settings, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	s.hideExpiredPause(settings)
+	return settings, nil
 }
 
 func (s *SettingService) GetSetting(key string) (*models.Setting, error) {
-	return s.repo.GetByKey(key)
+	if !salesPauseKeys[key] {
+		return s.repo.GetByKey(key)
+	}
+
+	// The pause settings depend on each other
+	settings, err := s.GetAllSettings()
+	if err != nil {
+		return nil, err
+	}
+	for i := range settings {
+		if settings[i].Key == key {
+			return &settings[i], nil
+		}
+	}
+	return nil, nil
 }
 
 func (s *SettingService) UpdateSetting(key string, value string, typeStr string) error {
+	if validate, ok := settingValidators[key]; ok {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSetting, err)
+		}
+	}
+
+	if key == "sales_paused" && value == "true" {
+		if err := s.clearExpiredResumeTime(); err != nil {
+			return err
+		}
+	}
+
 	setting := &models.Setting{
 		Key:   key,
 		Value: value,
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tonysanin/brobar/web-service/internal/models"
+)
+
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+func validateWorkingHours(value string) error {
+	var wh models.WorkingHours
+	if err := json.Unmarshal([]byte(value), &wh); err != nil {
+		return fmt.Errorf("invalid working hours: %w", err)
+	}
+
+	for name, days := range map[string]map[string]models.DaySchedule{"delivery": wh.Delivery, "pickup": wh.Pickup} {
+		for _, day := range weekdays {
+			schedule, ok := days[day]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", name, day)
+			}
+			if err := validateDaySchedule(schedule); err != nil {
+				return fmt.Errorf("%s.%s: %w", name, day, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateWorkingHoursOverrides(value string) error {
+	var overrides []models.ScheduleOverride
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return fmt.Errorf("invalid working hours overrides: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, override := range overrides {
+		if _, err := time.Parse("2006-01-02", override.Date); err != nil {
+			return fmt.Errorf("override %q: date must be YYYY-MM-DD", override.Date)
+		}
+		if seen[override.Date] {
+			return fmt.Errorf("override %s: duplicate date", override.Date)
+		}
+		seen[override.Date] = true
+
+		if err := validateDaySchedule(override.DaySchedule); err != nil {
+			return fmt.Errorf("override %s: %w", override.Date, err)
+		}
+	}
+
+	return nil
+}
+
+func validateDaySchedule(schedule models.DaySchedule) error {
+	if schedule.Closed {
+		return nil
+	}
+
+	start, err := time.Parse("15:04", schedule.Start)
+	if err != nil {
+		return fmt.Errorf("start must be HH:MM")
+	}
+	end, err := time.Parse("15:04", schedule.End)
+	if err != nil {
+		return fmt.Errorf("end must be HH:MM")
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+-- Remove sales pause details settings
+DELETE FROM settings WHERE key IN ('sales_paused_reason', 'sales_paused_until');
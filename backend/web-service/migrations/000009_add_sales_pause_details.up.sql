@@ -0,0 +1,3 @@
+-- Optional reason and resume time shown while sales are paused
+INSERT INTO settings (key, type, value) VALUES ('sales_paused_reason', 'string', '') ON CONFLICT DO NOTHING;
+INSERT INTO settings (key, type, value) VALUES ('sales_paused_until', 'string', '') ON CONFLICT DO NOTHING;
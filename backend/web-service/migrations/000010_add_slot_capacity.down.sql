@@ -0,0 +1,2 @@
+-- Remove time slot capacity setting
+DELETE FROM settings WHERE key = 'slot_capacity';
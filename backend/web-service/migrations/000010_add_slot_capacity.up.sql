@@ -0,0 +1,7 @@
+-- Time slot capacity per delivery type, 0 means unlimited
+INSERT INTO settings (key, type, value) VALUES ('slot_capacity', 'json', '{
+  "slot_minutes": 15,
+  "delivery": {"max_orders": 0, "max_items": 0},
+  "pickup": {"max_orders": 0, "max_items": 0},
+  "dine": {"max_orders": 0, "max_items": 0}
+}') ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- Remove working hours overrides setting
+DELETE FROM settings WHERE key = 'working_hours_overrides';
@@ -0,0 +1,2 @@
+-- Date-specific working hours: [{"date": "2026-12-31", "closed": true, "reason": "..."}]
+INSERT INTO settings (key, type, value) VALUES ('working_hours_overrides', 'json', '[]') ON CONFLICT DO NOTHING;
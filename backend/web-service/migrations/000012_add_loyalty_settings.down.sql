@@ -0,0 +1,2 @@
+-- Remove loyalty program setting
+DELETE FROM settings WHERE key = 'loyalty';
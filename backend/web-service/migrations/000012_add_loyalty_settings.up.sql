@@ -0,0 +1,6 @@
+-- Loyalty program: points earned per hryvnia and the share of an order payable with points
+INSERT INTO settings (key, type, value) VALUES ('loyalty', 'json', '{
+  "enabled": false,
+  "earn_rate": 0.05,
+  "max_redeem_percent": 30
+}') ON CONFLICT DO NOTHING;